@@ -0,0 +1,77 @@
+package i18n
+
+import (
+	"sort"
+	"sync"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// collatorPools 语言 -> Collator 对象池，Collator 不支持并发使用
+var collatorPools sync.Map
+
+func getCollator(lang string) (*collate.Collator, *sync.Pool) {
+	if pool, ok := collatorPools.Load(lang); ok {
+		p := pool.(*sync.Pool)
+		return p.Get().(*collate.Collator), p
+	}
+
+	tag, err := language.Parse(lang)
+	if err != nil {
+		tag = language.Und
+	}
+	pool, _ := collatorPools.LoadOrStore(lang, &sync.Pool{
+		New: func() interface{} {
+			// zh 默认按拼音排序
+			return collate.New(tag)
+		},
+	})
+	p := pool.(*sync.Pool)
+	return p.Get().(*collate.Collator), p
+}
+
+// CompareStrings 按语言规则比较字符串，a < b 返回 -1，a == b 返回 0，a > b 返回 1。
+func CompareStrings(lang string, a string, b string) int {
+	c, pool := getCollator(lang)
+	defer pool.Put(c)
+	return c.CompareString(a, b)
+}
+
+// SortStrings 按语言规则对字符串切片排序，例如 zh-CN 按拼音排序。
+func SortStrings(lang string, s []string) {
+	c, pool := getCollator(lang)
+	defer pool.Put(c)
+	c.SortStrings(s)
+}
+
+// SortByKey 按语言规则对切片排序，排序依据为 key 返回的字符串，排序是稳定的。
+func SortByKey[T any](lang string, items []T, key func(item T) string) {
+	c, pool := getCollator(lang)
+	defer pool.Put(c)
+
+	keys := make([]string, len(items))
+	for i, item := range items {
+		keys[i] = key(item)
+	}
+	sort.Stable(keyedSlice[T]{collator: c, items: items, keys: keys})
+}
+
+type keyedSlice[T any] struct {
+	collator *collate.Collator
+	items    []T
+	keys     []string
+}
+
+func (s keyedSlice[T]) Len() int {
+	return len(s.items)
+}
+
+func (s keyedSlice[T]) Less(i, j int) bool {
+	return s.collator.CompareString(s.keys[i], s.keys[j]) < 0
+}
+
+func (s keyedSlice[T]) Swap(i, j int) {
+	s.items[i], s.items[j] = s.items[j], s.items[i]
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+}
@@ -0,0 +1,45 @@
+package i18n
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortStrings(t *testing.T) {
+	tests := []struct {
+		lang string
+		in   []string
+		want []string
+	}{
+		// 按拼音：a、li、wang、zhang
+		{lang: "zh-CN", in: []string{"张三", "王五", "阿", "李四"}, want: []string{"阿", "李四", "王五", "张三"}},
+		{lang: "en-US", in: []string{"Zoë", "Émile", "zack", "Eve", "Ángel"}, want: []string{"Ángel", "Émile", "Eve", "zack", "Zoë"}},
+		// 瑞典语中 Ö 排在 Z 之后
+		{lang: "sv-SE", in: []string{"Östen", "Zelda", "Olof"}, want: []string{"Olof", "Zelda", "Östen"}},
+	}
+	for _, tt := range tests {
+		got := append([]string(nil), tt.in...)
+		SortStrings(tt.lang, got)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SortStrings(%s) = %v, want %v", tt.lang, got, tt.want)
+		}
+	}
+}
+
+func TestSortByKey(t *testing.T) {
+	type user struct {
+		Name string
+		ID   int
+	}
+	users := []user{{"张三", 1}, {"李四", 2}, {"张三", 3}, {"阿", 4}}
+	SortByKey("zh-CN", users, func(u user) string { return u.Name })
+
+	want := []user{{"阿", 4}, {"李四", 2}, {"张三", 1}, {"张三", 3}}
+	if !reflect.DeepEqual(users, want) {
+		t.Errorf("SortByKey = %v, want %v", users, want)
+	}
+
+	if CompareStrings("zh-CN", "阿", "张三") != -1 || CompareStrings("zh-CN", "张三", "张三") != 0 {
+		t.Error("CompareStrings should follow pinyin order")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"path"
 	"strings"
 	"sync"
+	"sync/atomic"
 	gotemplate "text/template"
 
 	"github.com/BurntSushi/toml"
@@ -15,22 +16,83 @@ import (
 )
 
 type Message struct {
-	Data           string
-	parseOnce      sync.Once
-	parsedTemplate *gotemplate.Template
+	Data string
+	// 加载时预编译的模板，不含模板语法时为 nil
+	tmpl *gotemplate.Template
 }
 
+// catalog 语言 -> messageId -> Message，发布后只读。
+type catalog map[string]map[string]*Message
+
 var (
-	iLocalizer = make(map[string]map[string]*Message)
-	leftDelim  = "{{"
+	// iLocalizer 当前生效的只读快照，加载新的语言文件时整体替换
+	iLocalizer atomic.Pointer[catalog]
+	// loadMu 串行化语言文件的加载
+	loadMu    sync.Mutex
+	leftDelim = "{{"
+
+	// bufPool 渲染模板使用的缓冲池
+	bufPool = sync.Pool{
+		New: func() interface{} {
+			return new(bytes.Buffer)
+		},
+	}
 )
 
-// RegisterI18n 语言类型map。
+func init() {
+	iLocalizer.Store(&catalog{})
+}
+
+// snapshot 获取当前生效的语言快照。
+func snapshot() catalog {
+	return *iLocalizer.Load()
+}
+
+// clone 复制快照，用于在其基础上加载新的语言文件。
+func (c catalog) clone() catalog {
+	nc := make(catalog, len(c))
+	for lang, mp := range c {
+		nmp := make(map[string]*Message, len(mp))
+		for k, v := range mp {
+			nmp[k] = v
+		}
+		nc[lang] = nmp
+	}
+	return nc
+}
+
+// RegisterI18n 语言类型map，加载失败时退出进程。
 func RegisterI18n(localeDir string) {
+	if err := LoadI18n(localeDir); err != nil {
+		log.Fatalf(err.Error())
+	}
+}
+
+// LoadI18n 与 RegisterI18n 相同，加载失败时返回错误，此时已加载的内容保持不变。
+func LoadI18n(localeDir string) error {
+	loadMu.Lock()
+	defer loadMu.Unlock()
+
+	// 在当前快照的副本上加载，全部成功后再原子替换
+	c := snapshot().clone()
+	if err := loadLocaleDir(c, localeDir); err != nil {
+		return err
+	}
+
+	if err := checkLanguageMap(c); err != nil {
+		return err
+	}
+
+	iLocalizer.Store(&c)
+	return nil
+}
+
+// loadLocaleDir 加载目录下的语言文件到 c 中。
+func loadLocaleDir(c catalog, localeDir string) error {
 	// get locale file list
 	fileInfos, err := os.ReadDir(localeDir)
 	if err != nil {
-		log.Fatalf("load locale dir %s failed: %v\n", localeDir, err)
+		return fmt.Errorf("load locale dir %s failed: %v", localeDir, err)
 	}
 
 	for _, fileInfos := range fileInfos {
@@ -40,14 +102,15 @@ func RegisterI18n(localeDir string) {
 			continue
 		}
 		if len(s) != 3 || s[2] != "toml" {
-			log.Fatalf("locale file %s filename format error, correct format is <module>.<language>.toml", fileInfos.Name())
-			return
+			return fmt.Errorf("locale file %s filename format error, correct format is <module>.<language>.toml", fileInfos.Name())
 		}
 
 		lang := s[1]
-		language.MustParse(lang)
-		if iLocalizer[lang] == nil {
-			iLocalizer[lang] = make(map[string]*Message)
+		if _, err = language.Parse(lang); err != nil {
+			return fmt.Errorf("locale file %s has invalid language %s: %v", fileInfos.Name(), lang, err)
+		}
+		if c[lang] == nil {
+			c[lang] = make(map[string]*Message)
 		}
 
 		filename := path.Join(localeDir, fileInfos.Name())
@@ -55,32 +118,26 @@ func RegisterI18n(localeDir string) {
 
 		buf, err := os.ReadFile(filename)
 		if err != nil {
-			log.Fatalf("load locale file %s failed: %v\n", filename, err)
-			return
+			return fmt.Errorf("load locale file %s failed: %v", filename, err)
 		}
 
 		var raw interface{}
 		if err = toml.Unmarshal(buf, &raw); err != nil {
-			log.Fatalf("Unmarshal locale file %s failed: %v\n", filename, err)
-			return
+			return fmt.Errorf("Unmarshal locale file %s failed: %v", filename, err)
 		}
 
-		if err = recGetMessages(lang, "", raw); err != nil {
-			log.Fatalf("recGetMessages failed: %v\n", err)
-			return
+		if err = recGetMessages(c[lang], lang, "", raw); err != nil {
+			return fmt.Errorf("recGetMessages failed: %v", err)
 		}
 	}
-
-	if err := checkLanguageMap(); err != nil {
-		log.Fatalf(err.Error())
-	}
+	return nil
 }
 
-func checkLanguageMap() error {
+func checkLanguageMap(c catalog) error {
 	first := true
 	var firstLang string
 	var firstMap map[string]*Message
-	for lang, mp := range iLocalizer {
+	for lang, mp := range c {
 		if first {
 			first = false
 			firstLang = lang
@@ -102,18 +159,20 @@ func checkLanguageMap() error {
 	return nil
 }
 
-func recGetMessages(lang string, messageId string, raw interface{}) error {
+func recGetMessages(localizer map[string]*Message, lang string, messageId string, raw interface{}) error {
 	switch data := raw.(type) {
 	case string:
 		if data == "" {
-			log.Fatalf("messageId %s is empty string", messageId)
+			return fmt.Errorf("messageId %s is empty string", messageId)
 		}
-		if oldMessage, ok := iLocalizer[lang][messageId]; ok {
-			log.Fatalf("messageId %s already exist, old data: %s, new data: %s\n", messageId, oldMessage.Data, data)
+		if oldMessage, ok := localizer[messageId]; ok {
+			return fmt.Errorf("messageId %s already exist, old data: %s, new data: %s", messageId, oldMessage.Data, data)
 		}
-		iLocalizer[lang][messageId] = &Message{
-			Data: data,
+		message, err := newMessage(data)
+		if err != nil {
+			return fmt.Errorf("messageId %s in localizer %s is incorrect, failed to parse the message, message data is '%s': %v", messageId, lang, data, err)
 		}
+		localizer[messageId] = message
 
 	case map[string]interface{}:
 		for k, v := range data {
@@ -121,7 +180,7 @@ func recGetMessages(lang string, messageId string, raw interface{}) error {
 			if messageId != "" {
 				k = messageId + "." + k
 			}
-			err := recGetMessages(lang, k, v)
+			err := recGetMessages(localizer, lang, k, v)
 			if err != nil {
 				return err
 			}
@@ -134,36 +193,93 @@ func recGetMessages(lang string, messageId string, raw interface{}) error {
 	return nil
 }
 
+// newMessage 创建 Message，含模板语法时预编译模板。
+func newMessage(data string) (*Message, error) {
+	message := &Message{
+		Data: data,
+	}
+	if !strings.Contains(data, leftDelim) {
+		return message, nil
+	}
+
+	tmpl, err := gotemplate.New("").Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	message.tmpl = tmpl
+	return message, nil
+}
+
 // Translate 根据语言获取对应的国际化内容。
 func Translate(lang string, messageId string, templateDate map[string]interface{}) string {
-	localizer, ok := iLocalizer[lang]
-	if !ok {
-		log.Fatalf("the localizer of %s is not exist", lang)
+	text, err := TranslateWithError(lang, messageId, templateDate)
+	if err != nil {
+		log.Fatalf(err.Error())
 		return ""
 	}
+	return text
+}
+
+// TranslateWithError 根据语言获取对应的国际化内容，语言或 messageId 不存在时返回错误。
+func TranslateWithError(lang string, messageId string, templateDate map[string]interface{}) (string, error) {
+	localizer, ok := snapshot()[lang]
+	if !ok {
+		return "", fmt.Errorf("the localizer of %s is not exist", lang)
+	}
 
 	message, ok := localizer[messageId]
 	if !ok {
-		log.Fatalf("the messageId %s in localizer %s is not exist", messageId, lang)
-		return ""
+		return "", fmt.Errorf("the messageId %s in localizer %s is not exist", messageId, lang)
 	}
 
-	if !strings.Contains(message.Data, leftDelim) {
-		return message.Data
+	return message.render(lang, messageId, templateDate)
+}
+
+// MessageData 获取未渲染的原始内容，模板参数保持 {{.Name}} 形式，用于生成文档。
+func MessageData(lang string, messageId string) (string, error) {
+	localizer, ok := snapshot()[lang]
+	if !ok {
+		return "", fmt.Errorf("the localizer of %s is not exist", lang)
 	}
 
-	var err error
-	message.parseOnce.Do(func() {
-		message.parsedTemplate, err = gotemplate.New("").Parse(message.Data)
-		if err != nil {
-			log.Fatalf("messageId %s in localizer %s is incorrect, failed to parse the message, message data is '%s'", messageId, lang, message.Data)
-		}
-	})
+	message, ok := localizer[messageId]
+	if !ok {
+		return "", fmt.Errorf("the messageId %s in localizer %s is not exist", messageId, lang)
+	}
+	return message.Data, nil
+}
 
-	var buf bytes.Buffer
-	if err := message.parsedTemplate.Execute(&buf, templateDate); err != nil {
-		log.Fatalf("messageId %s in localizer %s is incorrect, failed to execute the message, message data is '%s', template data is %v", messageId, lang, message.Data, templateDate)
-		return ""
+func (m *Message) render(lang string, messageId string, templateDate map[string]interface{}) (string, error) {
+	if m.tmpl == nil {
+		return m.Data, nil
+	}
+
+	buf := bufPool.Get().(*bytes.Buffer)
+	defer func() {
+		buf.Reset()
+		bufPool.Put(buf)
+	}()
+
+	if err := m.tmpl.Execute(buf, templateDate); err != nil {
+		return "", fmt.Errorf("messageId %s in localizer %s is incorrect, failed to execute the message, message data is '%s', template data is %v", messageId, lang, m.Data, templateDate)
+	}
+	return buf.String(), nil
+}
+
+// Reset 清空已加载的语言文件和租户覆盖，返回恢复之前状态的函数，用于测试隔离。
+func Reset() (restore func()) {
+	loadMu.Lock()
+	defer loadMu.Unlock()
+
+	old := iLocalizer.Load()
+	oldTenants := tenantLocalizer.Load()
+	iLocalizer.Store(&catalog{})
+	tenantLocalizer.Store(&map[string]catalog{})
+
+	return func() {
+		loadMu.Lock()
+		defer loadMu.Unlock()
+		iLocalizer.Store(old)
+		tenantLocalizer.Store(oldTenants)
 	}
-	return buf.String()
 }
@@ -0,0 +1,88 @@
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sync"
+	"testing"
+)
+
+func loadTestLocale(tb testing.TB) {
+	tb.Helper()
+
+	restore := Reset()
+	tb.Cleanup(restore)
+	RegisterI18n("testdata/locale")
+}
+
+func BenchmarkTranslate(b *testing.B) {
+	loadTestLocale(b)
+
+	b.Run("plain", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			Translate("en-US", "Demo.Plain", nil)
+		}
+	})
+
+	b.Run("template", func(b *testing.B) {
+		data := map[string]interface{}{"Name": "Rocky"}
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			Translate("en-US", "Demo.Template", data)
+		}
+	})
+}
+
+// TestTranslateDuringRegister 加载语言文件的同时并发翻译，需配合 -race 运行。
+func TestTranslateDuringRegister(t *testing.T) {
+	loadTestLocale(t)
+
+	// 每个目录包含不同的 messageId，避免重复加载
+	dirs := make([]string, 10)
+	for i := range dirs {
+		dirs[i] = t.TempDir()
+		for _, lang := range []string{"zh-CN", "en-US"} {
+			content := fmt.Sprintf("[Extra%d]\nText = \"extra {{.N}}\"\n", i)
+			filename := path.Join(dirs[i], fmt.Sprintf("extra.%s.toml", lang))
+			if err := os.WriteFile(filename, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			data := map[string]interface{}{"Name": "Rocky"}
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				if got := Translate("en-US", "Demo.Template", data); got != "Hello, Rocky" {
+					t.Errorf("Translate = %q, want %q", got, "Hello, Rocky")
+					return
+				}
+			}
+		}()
+	}
+
+	for _, dir := range dirs {
+		RegisterI18n(dir)
+	}
+	close(stop)
+	wg.Wait()
+
+	for i := range dirs {
+		want := fmt.Sprintf("extra %d", i)
+		if got := Translate("zh-CN", fmt.Sprintf("Extra%d.Text", i), map[string]interface{}{"N": i}); got != want {
+			t.Errorf("Translate = %q, want %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package i18n
+
+import (
+	"os"
+	"path"
+	"testing"
+)
+
+func TestLoadI18nError(t *testing.T) {
+	loadTestLocale(t)
+
+	// 重复加载同一目录时 messageId 重复
+	if err := LoadI18n("testdata/locale"); err == nil {
+		t.Error("LoadI18n should fail on duplicate messageIds")
+	}
+	if err := LoadI18n(path.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("LoadI18n should fail on a missing dir")
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(path.Join(dir, "bad.en-US.toml"), []byte("[Bad]\nText = \"{{.Name\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadI18n(dir); err == nil {
+		t.Error("LoadI18n should fail on an invalid template")
+	}
+
+	// 失败的加载不影响已加载的内容
+	if got := Translate("en-US", "Demo.Plain", nil); got != "Hello" {
+		t.Errorf("Translate = %q, want %q", got, "Hello")
+	}
+	if _, err := TranslateWithError("en-US", "Bad.Text", nil); err == nil {
+		t.Error("messages of a failed load should not be published")
+	}
+}
@@ -0,0 +1,119 @@
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"golang.org/x/text/language"
+)
+
+// LocalizedString 多语言文本，key为语言，value为对应语言的内容。
+type LocalizedString map[string]string
+
+// NewLocalizedString 创建多语言文本，语言会被规范化，例如 zh-cn 转换为 zh-CN。
+func NewLocalizedString(texts map[string]string) (LocalizedString, error) {
+	s := make(LocalizedString, len(texts))
+	for lang, text := range texts {
+		tag, err := language.Parse(lang)
+		if err != nil {
+			return nil, fmt.Errorf("invalid lang %s in localized string: %v", lang, err)
+		}
+		s[tag.String()] = text
+	}
+	return s, nil
+}
+
+// Get 根据语言获取文本。
+// 优先精确匹配，其次按语言相近程度匹配（例如 zh 匹配 zh-CN），最后依次尝试 fallbackLangs。
+func (s LocalizedString) Get(lang string, fallbackLangs ...string) string {
+	if len(s) == 0 {
+		return ""
+	}
+
+	if text, ok := s[lang]; ok {
+		return text
+	}
+
+	if lang != "" {
+		if text, ok := s.match(lang); ok {
+			return text
+		}
+	}
+
+	for _, fallbackLang := range fallbackLangs {
+		if text, ok := s[fallbackLang]; ok {
+			return text
+		}
+	}
+	return ""
+}
+
+func (s LocalizedString) match(lang string) (string, bool) {
+	want, err := language.Parse(lang)
+	if err != nil {
+		return "", false
+	}
+
+	langs := make([]string, 0, len(s))
+	tags := make([]language.Tag, 0, len(s))
+	for k := range s {
+		tag, err := language.Parse(k)
+		if err != nil {
+			continue
+		}
+		langs = append(langs, k)
+		tags = append(tags, tag)
+	}
+	if len(tags) == 0 {
+		return "", false
+	}
+
+	_, index, confidence := language.NewMatcher(tags).Match(want)
+	if confidence == language.No {
+		return "", false
+	}
+	return s[langs[index]], true
+}
+
+// UnmarshalJSON 反序列化JSON对象，并规范化语言。
+func (s *LocalizedString) UnmarshalJSON(data []byte) error {
+	var texts map[string]string
+	if err := json.Unmarshal(data, &texts); err != nil {
+		return err
+	}
+	if texts == nil {
+		*s = nil
+		return nil
+	}
+
+	ls, err := NewLocalizedString(texts)
+	if err != nil {
+		return err
+	}
+	*s = ls
+	return nil
+}
+
+// UnmarshalTOML 反序列化TOML表，并规范化语言。
+func (s *LocalizedString) UnmarshalTOML(data interface{}) error {
+	raw, ok := data.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("unsupported localized string format %T: %v", data, data)
+	}
+
+	texts := make(map[string]string, len(raw))
+	for lang, v := range raw {
+		text, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("unsupported localized text format %T of lang %s: %v", v, lang, v)
+		}
+		texts[lang] = text
+	}
+
+	ls, err := NewLocalizedString(texts)
+	if err != nil {
+		return err
+	}
+	*s = ls
+	return nil
+}
@@ -0,0 +1,30 @@
+package i18n
+
+import (
+	"time"
+)
+
+// InLocation 将模板参数中的时间转换到指定时区，返回新的模板参数，原参数不变。
+func InLocation(templateData map[string]interface{}, loc *time.Location) map[string]interface{} {
+	if templateData == nil || loc == nil {
+		return templateData
+	}
+
+	data := make(map[string]interface{}, len(templateData))
+	for k, v := range templateData {
+		switch t := v.(type) {
+		case time.Time:
+			data[k] = t.In(loc)
+		case *time.Time:
+			if t != nil {
+				inLoc := t.In(loc)
+				data[k] = &inLoc
+			} else {
+				data[k] = t
+			}
+		default:
+			data[k] = v
+		}
+	}
+	return data
+}
@@ -0,0 +1,35 @@
+package i18n
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInLocation(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("timezone database unavailable: %v", err)
+	}
+
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var nilTime *time.Time
+	data := map[string]interface{}{"At": at, "AtPtr": &at, "Nil": nilTime, "Name": "rocky"}
+
+	got := InLocation(data, tokyo)
+	if got["At"].(time.Time).Location() != tokyo || got["AtPtr"].(*time.Time).Location() != tokyo {
+		t.Errorf("times should be converted to %s: %v", tokyo, got)
+	}
+	if got["Nil"].(*time.Time) != nil || got["Name"] != "rocky" {
+		t.Errorf("other values should be kept: %v", got)
+	}
+	if data["At"].(time.Time).Location() != time.UTC || data["AtPtr"].(*time.Time) != &at {
+		t.Error("InLocation should not change the original data")
+	}
+
+	if InLocation(nil, tokyo) != nil {
+		t.Error("nil data should stay nil")
+	}
+	if got := InLocation(data, nil); got["At"].(time.Time).Location() != time.UTC {
+		t.Error("nil location should keep the data unchanged")
+	}
+}
@@ -0,0 +1,91 @@
+package i18n
+
+import (
+	"fmt"
+	"log"
+	"sync/atomic"
+)
+
+// tenantLocalizer 租户ID -> 租户覆盖的语言快照，发布后只读。
+var tenantLocalizer atomic.Pointer[map[string]catalog]
+
+func init() {
+	tenantLocalizer.Store(&map[string]catalog{})
+}
+
+func tenantSnapshot() map[string]catalog {
+	return *tenantLocalizer.Load()
+}
+
+// RegisterTenantI18n 加载租户的覆盖语言文件，文件格式与 RegisterI18n 相同，加载失败时退出进程。
+// 租户只需提供需要覆盖的 messageId，未覆盖的内容使用基础语言文件。
+func RegisterTenantI18n(tenant string, localeDir string) {
+	if err := LoadTenantI18n(tenant, localeDir); err != nil {
+		log.Fatalf(err.Error())
+	}
+}
+
+// LoadTenantI18n 与 RegisterTenantI18n 相同，加载失败时返回错误，此时已加载的内容保持不变。
+func LoadTenantI18n(tenant string, localeDir string) error {
+	loadMu.Lock()
+	defer loadMu.Unlock()
+
+	old := tenantSnapshot()
+	tenants := make(map[string]catalog, len(old)+1)
+	for k, v := range old {
+		tenants[k] = v
+	}
+
+	c := catalog{}
+	if tc, ok := old[tenant]; ok {
+		c = tc.clone()
+	}
+	if err := loadLocaleDir(c, localeDir); err != nil {
+		return fmt.Errorf("tenant %s: %v", tenant, err)
+	}
+	tenants[tenant] = c
+
+	tenantLocalizer.Store(&tenants)
+	return nil
+}
+
+// lookupTenant 查找租户覆盖的 Message。
+func lookupTenant(tenant string, lang string, messageId string) (*Message, bool) {
+	if tenant == "" {
+		return nil, false
+	}
+	c, ok := tenantSnapshot()[tenant]
+	if !ok {
+		return nil, false
+	}
+	message, ok := c[lang][messageId]
+	return message, ok
+}
+
+// HasTenantMessage 判断租户是否覆盖了指定语言的 messageId。
+func HasTenantMessage(tenant string, lang string, messageId string) bool {
+	_, ok := lookupTenant(tenant, lang, messageId)
+	return ok
+}
+
+// TranslateTenant 根据租户和语言获取国际化内容，租户未覆盖时使用 Translate。
+func TranslateTenant(tenant string, lang string, messageId string, templateDate map[string]interface{}) string {
+	text, err := TranslateTenantWithError(tenant, lang, messageId, templateDate)
+	if err != nil {
+		log.Fatalf(err.Error())
+		return ""
+	}
+	return text
+}
+
+// TranslateTenantWithError 根据租户和语言获取国际化内容，租户未覆盖时使用 TranslateWithError。
+func TranslateTenantWithError(tenant string, lang string, messageId string, templateDate map[string]interface{}) (string, error) {
+	if message, ok := lookupTenant(tenant, lang, messageId); ok {
+		text, err := message.render(lang, messageId, templateDate)
+		if err != nil {
+			return "", fmt.Errorf("tenant %s: %v", tenant, err)
+		}
+		return text, nil
+	}
+	return TranslateWithError(lang, messageId, templateDate)
+}
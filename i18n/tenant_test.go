@@ -0,0 +1,67 @@
+package i18n
+
+import (
+	"os"
+	"path"
+	"testing"
+)
+
+func TestTranslateTenant(t *testing.T) {
+	loadTestLocale(t)
+	if err := LoadTenantI18n("acme", "testdata/tenant"); err != nil {
+		t.Fatal(err)
+	}
+
+	data := map[string]interface{}{"Name": "Rocky"}
+	tests := []struct {
+		tenant    string
+		messageId string
+		want      string
+	}{
+		{tenant: "acme", messageId: "Demo.Template", want: "Welcome, Rocky"},
+		// 租户未覆盖的 messageId 使用基础语言文件
+		{tenant: "acme", messageId: "Demo.Plain", want: "Hello"},
+		{tenant: "other", messageId: "Demo.Template", want: "Hello, Rocky"},
+		{tenant: "", messageId: "Demo.Template", want: "Hello, Rocky"},
+	}
+	for _, tt := range tests {
+		if got := TranslateTenant(tt.tenant, "en-US", tt.messageId, data); got != tt.want {
+			t.Errorf("TranslateTenant(%q, %q) = %q, want %q", tt.tenant, tt.messageId, got, tt.want)
+		}
+	}
+
+	if !HasTenantMessage("acme", "zh-CN", "Demo.Template") {
+		t.Error("acme should override Demo.Template")
+	}
+	if HasTenantMessage("acme", "zh-CN", "Demo.Plain") || HasTenantMessage("other", "zh-CN", "Demo.Template") {
+		t.Error("HasTenantMessage should be false for messages the tenant does not override")
+	}
+}
+
+func TestLoadTenantI18nError(t *testing.T) {
+	loadTestLocale(t)
+	if err := LoadTenantI18n("acme", "testdata/tenant"); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	for _, file := range []struct{ name, content string }{
+		{name: "extra.en-US.toml", content: "[Extra]\nText = \"extra\"\n"},
+		{name: "zbad.en-US.toml", content: "[Bad]\nText = \"\"\n"},
+	} {
+		if err := os.WriteFile(path.Join(dir, file.name), []byte(file.content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := LoadTenantI18n("acme", dir); err == nil {
+		t.Fatal("LoadTenantI18n should fail on an empty message")
+	}
+
+	// extra 先于 zbad 加载，失败后也不能生效；之前的租户快照保持不变
+	if HasTenantMessage("acme", "en-US", "Extra.Text") {
+		t.Error("messages of a failed load should not be published")
+	}
+	if got := TranslateTenant("acme", "en-US", "Demo.Template", map[string]interface{}{"Name": "Rocky"}); got != "Welcome, Rocky" {
+		t.Errorf("TranslateTenant = %q, want %q", got, "Welcome, Rocky")
+	}
+}
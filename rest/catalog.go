@@ -0,0 +1,138 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorCatalogEntry 错误码目录条目。
+type ErrorCatalogEntry struct {
+	ErrorCode   string   `json:"error_code"`  // 错误码
+	Description string   `json:"description"` // 错误描述
+	Solution    string   `json:"solution"`    // 解决方法
+	ErrorLink   string   `json:"error_link"`  // 错误链接
+	HTTPStatus  int      `json:"http_status"` // 默认HTTP状态码
+	Retryable   bool     `json:"retryable"`   // 是否可重试
+	Severity    Severity `json:"severity"`    // 日志级别
+	Category    string   `json:"category"`    // 分类
+}
+
+// Catalog 返回所有已注册错误码指定语言的目录，按错误码排序。
+// 注册错误码的描述和解决方法为语言文件中的原始文案，模板参数保持 {{.Name}} 形式；
+// 系统默认错误为不带参数渲染的文案。
+func (r *ErrorRegistry) Catalog(lang string) []ErrorCatalogEntry {
+	codes := r.Codes()
+	entries := make([]ErrorCatalogEntry, 0, len(codes))
+	for _, errorCode := range codes {
+		baseErr, ok := r.lookupRaw(errorCode, lang)
+		if !ok {
+			baseErr, ok = r.Lookup(errorCode, lang)
+		}
+		if !ok {
+			continue
+		}
+
+		entry := ErrorCatalogEntry{
+			ErrorCode:   errorCode,
+			Description: baseErr.Description,
+			Solution:    baseErr.Solution,
+			ErrorLink:   resolveErrorLink(errorCode, lang, baseErr.ErrorLink),
+			HTTPStatus:  DefaultHTTPStatus,
+		}
+		if def, ok := r.Definition(errorCode); ok {
+			if def.HTTPStatus != 0 {
+				entry.HTTPStatus = def.HTTPStatus
+			}
+			entry.Retryable = def.Retryable
+			entry.Severity = def.Severity
+			entry.Category = def.Category
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// ErrorCatalogHandler 返回错误码目录的 gin handler，语言取自请求的 X-Language。
+func ErrorCatalogHandler(r *ErrorRegistry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lang := GetLanguageByCtx(GetLanguageCtx(c))
+		ReplyOK(c, http.StatusOK, r.Catalog(lang))
+	}
+}
+
+// WriteErrorCatalogMarkdown 将错误码目录以 Markdown 表格写入 w。
+func (r *ErrorRegistry) WriteErrorCatalogMarkdown(w io.Writer, lang string) error {
+	var b strings.Builder
+	b.WriteString("| error_code | http_status | description | solution | error_link | retryable | severity | category |\n")
+	b.WriteString("| --- | --- | --- | --- | --- | --- | --- | --- |\n")
+	for _, entry := range r.Catalog(lang) {
+		fmt.Fprintf(&b, "| %s | %d | %s | %s | %s | %t | %s | %s |\n",
+			escapeMarkdown(entry.ErrorCode), entry.HTTPStatus, escapeMarkdown(entry.Description),
+			escapeMarkdown(entry.Solution), escapeMarkdown(entry.ErrorLink), entry.Retryable,
+			entry.Severity, escapeMarkdown(entry.Category))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func escapeMarkdown(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// WriteErrorCatalogOpenAPI 将错误码目录以 OpenAPI components 片段（JSON）写入 w。
+// 包含 BaseError schema，以及每个错误码一个引用该 schema 的 response。
+func (r *ErrorRegistry) WriteErrorCatalogOpenAPI(w io.Writer, lang string) error {
+	responses := make(map[string]interface{})
+	for _, entry := range r.Catalog(lang) {
+		responses[entry.ErrorCode] = map[string]interface{}{
+			"description": fmt.Sprintf("%d %s", entry.HTTPStatus, entry.Description),
+			"content": map[string]interface{}{
+				ContentTypeJson: map[string]interface{}{
+					"schema": map[string]interface{}{
+						"$ref": "#/components/schemas/BaseError",
+					},
+					"example": BaseError{
+						ErrorCode:   entry.ErrorCode,
+						Description: entry.Description,
+						Solution:    entry.Solution,
+						ErrorLink:   entry.ErrorLink,
+					},
+				},
+			},
+		}
+	}
+
+	doc := map[string]interface{}{
+		"components": map[string]interface{}{
+			"schemas": map[string]interface{}{
+				"BaseError": baseErrorSchema,
+			},
+			"responses": responses,
+		},
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+var baseErrorSchema = map[string]interface{}{
+	"type":     "object",
+	"required": []string{"error_code", "description", "solution", "error_link"},
+	"properties": map[string]interface{}{
+		"error_code":    map[string]interface{}{"type": "string", "description": "错误码"},
+		"description":   map[string]interface{}{"type": "string", "description": "错误描述"},
+		"solution":      map[string]interface{}{"type": "string", "description": "解决方法"},
+		"error_link":    map[string]interface{}{"type": "string", "description": "错误链接"},
+		"error_details": map[string]interface{}{"description": "详细内容"},
+		"request_id":    map[string]interface{}{"type": "string", "description": "请求ID"},
+		"trace_id":      map[string]interface{}{"type": "string", "description": "trace ID"},
+	},
+}
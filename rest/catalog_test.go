@@ -0,0 +1,148 @@
+package rest_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/RockyRori/AdoLib/rest"
+	"github.com/RockyRori/AdoLib/rest/resttest"
+	"github.com/gin-gonic/gin"
+)
+
+func catalogRegistry(t *testing.T) *rest.ErrorRegistry {
+	t.Helper()
+
+	resttest.LoadLocale(t, "testdata/locale")
+	registry := rest.NewErrorRegistry()
+	err := registry.RegisterDefinitions([]rest.ErrorDefinition{
+		{Code: "DemoUserNotFound", HTTPStatus: http.StatusNotFound, Severity: rest.SeverityInfo, Category: "user"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return registry
+}
+
+func findEntry(t *testing.T, entries []rest.ErrorCatalogEntry, errorCode string) rest.ErrorCatalogEntry {
+	t.Helper()
+
+	for _, entry := range entries {
+		if entry.ErrorCode == errorCode {
+			return entry
+		}
+	}
+	t.Fatalf("catalog missing %s", errorCode)
+	return rest.ErrorCatalogEntry{}
+}
+
+func TestCatalog(t *testing.T) {
+	registry := catalogRegistry(t)
+
+	entries := registry.Catalog("en-US")
+	if len(entries) != len(registry.Codes()) {
+		t.Errorf("got %d entries, want %d", len(entries), len(registry.Codes()))
+	}
+
+	got := findEntry(t, entries, "DemoUserNotFound")
+	want := rest.ErrorCatalogEntry{
+		ErrorCode:   "DemoUserNotFound",
+		Description: "User {{.Name}} does not exist",
+		Solution:    "Please check the user name",
+		HTTPStatus:  http.StatusNotFound,
+		Severity:    rest.SeverityInfo,
+		Category:    "user",
+	}
+	if got != want {
+		t.Errorf("entry = %+v, want %+v", got, want)
+	}
+
+	notFound := findEntry(t, entries, rest.NotFound)
+	if notFound.Description != "Resource not found" || notFound.HTTPStatus != http.StatusNotFound {
+		t.Errorf("NotFound entry = %+v", notFound)
+	}
+}
+
+func TestErrorCatalogHandler(t *testing.T) {
+	registry := catalogRegistry(t)
+	engine := resttest.NewEngine(resttest.Route{
+		Method:   http.MethodGet,
+		Path:     "/errors",
+		Handlers: []gin.HandlerFunc{rest.ErrorCatalogHandler(registry)},
+	})
+
+	resp := resttest.Do(t, engine, resttest.Request{Method: http.MethodGet, Path: "/errors", Language: "zh-CN"})
+	resp.AssertStatus(t, http.StatusOK)
+
+	var entries []rest.ErrorCatalogEntry
+	if err := json.Unmarshal(resp.Body.Bytes(), &entries); err != nil {
+		t.Fatal(err)
+	}
+	if got := findEntry(t, entries, "DemoUserNotFound").Description; got != "用户 {{.Name}} 不存在" {
+		t.Errorf("description = %q, want %q", got, "用户 {{.Name}} 不存在")
+	}
+}
+
+func TestWriteErrorCatalogMarkdown(t *testing.T) {
+	registry := catalogRegistry(t)
+
+	var buf bytes.Buffer
+	if err := registry.WriteErrorCatalogMarkdown(&buf, "en-US"); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != len(registry.Codes())+2 {
+		t.Errorf("got %d lines, want header, separator and one row per code", len(lines))
+	}
+	want := "| DemoUserNotFound | 404 | User {{.Name}} does not exist | Please check the user name |  | false | info | user |"
+	if !strings.Contains(buf.String(), want+"\n") {
+		t.Errorf("markdown missing row %q:\n%s", want, buf.String())
+	}
+}
+
+func TestWriteErrorCatalogOpenAPI(t *testing.T) {
+	registry := catalogRegistry(t)
+
+	var buf bytes.Buffer
+	if err := registry.WriteErrorCatalogOpenAPI(&buf, "en-US"); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc struct {
+		Components struct {
+			Schemas struct {
+				BaseError struct {
+					Properties map[string]interface{} `json:"properties"`
+				} `json:"BaseError"`
+			} `json:"schemas"`
+			Responses map[string]struct {
+				Description string `json:"description"`
+				Content     map[string]struct {
+					Example rest.BaseError `json:"example"`
+				} `json:"content"`
+			} `json:"responses"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, property := range []string{"error_code", "description", "solution", "error_link", "error_details", "request_id", "trace_id"} {
+		if _, ok := doc.Components.Schemas.BaseError.Properties[property]; !ok {
+			t.Errorf("BaseError schema missing property %s", property)
+		}
+	}
+
+	response, ok := doc.Components.Responses["DemoUserNotFound"]
+	if !ok {
+		t.Fatal("missing DemoUserNotFound response")
+	}
+	if want := "404 User {{.Name}} does not exist"; response.Description != want {
+		t.Errorf("description = %q, want %q", response.Description, want)
+	}
+	if got := response.Content[rest.ContentTypeJson].Example.Description; got != "User {{.Name}} does not exist" {
+		t.Errorf("example description = %q", got)
+	}
+}
@@ -0,0 +1,17 @@
+package rest
+
+import (
+	"context"
+
+	. "github.com/RockyRori/AdoLib/i18n"
+)
+
+// SortStringsByCtx 按 context 中语言的排序规则对字符串切片排序。
+func SortStringsByCtx(ctx context.Context, s []string) {
+	SortStrings(GetLanguageByCtx(ctx), s)
+}
+
+// SortByKeyCtx 按 context 中语言的排序规则对切片排序，排序依据为 key 返回的字符串。
+func SortByKeyCtx[T any](ctx context.Context, items []T, key func(item T) string) {
+	SortByKey(GetLanguageByCtx(ctx), items, key)
+}
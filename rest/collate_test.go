@@ -0,0 +1,26 @@
+package rest_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/RockyRori/AdoLib/rest"
+)
+
+func TestSortByCtx(t *testing.T) {
+	zh := context.WithValue(context.Background(), rest.XLangKey, "zh-CN")
+	names := []string{"张三", "王五", "阿", "李四"}
+	rest.SortStringsByCtx(zh, names)
+	if want := []string{"阿", "李四", "王五", "张三"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("SortStringsByCtx = %v, want %v", names, want)
+	}
+
+	type city struct{ Name string }
+	en := context.WithValue(context.Background(), rest.XLangKey, "en-US")
+	cities := []city{{"Zürich"}, {"Ōsaka"}, {"Berlin"}}
+	rest.SortByKeyCtx(en, cities, func(c city) string { return c.Name })
+	if want := []city{{"Berlin"}, {"Ōsaka"}, {"Zürich"}}; !reflect.DeepEqual(cities, want) {
+		t.Errorf("SortByKeyCtx = %v, want %v", cities, want)
+	}
+}
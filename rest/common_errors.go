@@ -1,12 +1,42 @@
 package rest
 
+import (
+	"bytes"
+	"net/http"
+	gotemplate "text/template"
+)
+
 // 系统默认错误
 const (
 	// InternalError 通用错误码，服务端内部错误
 	InternalError = "InternalError"
+	// ValidationError 通用错误码，请求参数校验失败
+	ValidationError = "ValidationError"
+	// BadRequest 通用错误码，请求错误
+	BadRequest = "BadRequest"
+	// Unauthorized 通用错误码，未认证
+	Unauthorized = "Unauthorized"
+	// Forbidden 通用错误码，无权限，描述参数 Action
+	Forbidden = "Forbidden"
+	// NotFound 通用错误码，资源不存在，描述参数 Resource
+	NotFound = "NotFound"
+	// Conflict 通用错误码，资源冲突，描述参数 Resource
+	Conflict = "Conflict"
+	// NotAcceptable 通用错误码，没有可接受的响应格式
+	NotAcceptable = "NotAcceptable"
+	// PayloadTooLarge 通用错误码，请求体过大，描述参数 Limit
+	PayloadTooLarge = "PayloadTooLarge"
+	// TooManyRequests 通用错误码，请求过于频繁，解决方法参数 RetryAfter
+	TooManyRequests = "TooManyRequests"
+	// ServiceUnavailable 通用错误码，服务不可用，描述参数 Service
+	ServiceUnavailable = "ServiceUnavailable"
+	// GatewayTimeout 通用错误码，上游服务超时，描述参数 Service
+	GatewayTimeout = "GatewayTimeout"
 )
 
 var (
+	// commonErrorI18n 系统默认错误的国际化内容，Description 和 Solution 支持模板参数，
+	// 未传参数时按无参数的文案渲染
 	commonErrorI18n = map[string]map[string]BaseError{
 		InternalError: {
 			"zh-CN": {
@@ -22,5 +52,210 @@ var (
 				ErrorLink:   "None",
 			},
 		},
+		ValidationError: {
+			"zh-CN": {
+				ErrorCode:   ValidationError,
+				Description: "请求参数校验失败",
+				Solution:    "请根据错误详情修改请求参数",
+				ErrorLink:   "暂无",
+			},
+			"en-US": {
+				ErrorCode:   ValidationError,
+				Description: "Request validation failed",
+				Solution:    "Correct the request parameters according to the error details",
+				ErrorLink:   "None",
+			},
+		},
+		BadRequest: {
+			"zh-CN": {
+				ErrorCode:   BadRequest,
+				Description: "请求错误",
+				Solution:    "请检查请求参数",
+				ErrorLink:   "暂无",
+			},
+			"en-US": {
+				ErrorCode:   BadRequest,
+				Description: "Bad Request",
+				Solution:    "Check the request parameters",
+				ErrorLink:   "None",
+			},
+		},
+		Unauthorized: {
+			"zh-CN": {
+				ErrorCode:   Unauthorized,
+				Description: "未认证或认证已过期",
+				Solution:    "请重新登录",
+				ErrorLink:   "暂无",
+			},
+			"en-US": {
+				ErrorCode:   Unauthorized,
+				Description: "Unauthorized",
+				Solution:    "Sign in again",
+				ErrorLink:   "None",
+			},
+		},
+		Forbidden: {
+			"zh-CN": {
+				ErrorCode:   Forbidden,
+				Description: "无权限{{if .Action}}执行{{.Action}}{{end}}",
+				Solution:    "请联系管理员授权",
+				ErrorLink:   "暂无",
+			},
+			"en-US": {
+				ErrorCode:   Forbidden,
+				Description: "Forbidden{{if .Action}}: not allowed to {{.Action}}{{end}}",
+				Solution:    "Contact the administrator for permission",
+				ErrorLink:   "None",
+			},
+		},
+		NotFound: {
+			"zh-CN": {
+				ErrorCode:   NotFound,
+				Description: "{{if .Resource}}{{.Resource}}{{else}}资源{{end}}不存在",
+				Solution:    "请检查资源是否已被删除",
+				ErrorLink:   "暂无",
+			},
+			"en-US": {
+				ErrorCode:   NotFound,
+				Description: "{{if .Resource}}{{.Resource}}{{else}}Resource{{end}} not found",
+				Solution:    "Check whether the resource has been deleted",
+				ErrorLink:   "None",
+			},
+		},
+		Conflict: {
+			"zh-CN": {
+				ErrorCode:   Conflict,
+				Description: "{{if .Resource}}{{.Resource}}{{else}}资源{{end}}冲突",
+				Solution:    "请刷新后重试",
+				ErrorLink:   "暂无",
+			},
+			"en-US": {
+				ErrorCode:   Conflict,
+				Description: "{{if .Resource}}{{.Resource}}{{else}}Resource{{end}} conflict",
+				Solution:    "Refresh and try again",
+				ErrorLink:   "None",
+			},
+		},
+		NotAcceptable: {
+			"zh-CN": {
+				ErrorCode:   NotAcceptable,
+				Description: "不支持请求的响应格式",
+				Solution:    "请将 Accept 设置为 application/json、application/xml 或 application/yaml",
+				ErrorLink:   "暂无",
+			},
+			"en-US": {
+				ErrorCode:   NotAcceptable,
+				Description: "Not Acceptable",
+				Solution:    "Set Accept to application/json, application/xml or application/yaml",
+				ErrorLink:   "None",
+			},
+		},
+		PayloadTooLarge: {
+			"zh-CN": {
+				ErrorCode:   PayloadTooLarge,
+				Description: "请求体过大{{if .Limit}}，上限为{{.Limit}}{{end}}",
+				Solution:    "请减小请求体",
+				ErrorLink:   "暂无",
+			},
+			"en-US": {
+				ErrorCode:   PayloadTooLarge,
+				Description: "Payload too large{{if .Limit}}, the limit is {{.Limit}}{{end}}",
+				Solution:    "Reduce the request payload",
+				ErrorLink:   "None",
+			},
+		},
+		TooManyRequests: {
+			"zh-CN": {
+				ErrorCode:   TooManyRequests,
+				Description: "请求过于频繁",
+				Solution:    "请{{if .RetryAfter}}{{.RetryAfter}}后{{else}}稍后{{end}}重试",
+				ErrorLink:   "暂无",
+			},
+			"en-US": {
+				ErrorCode:   TooManyRequests,
+				Description: "Too many requests",
+				Solution:    "Try again {{if .RetryAfter}}after {{.RetryAfter}}{{else}}later{{end}}",
+				ErrorLink:   "None",
+			},
+		},
+		ServiceUnavailable: {
+			"zh-CN": {
+				ErrorCode:   ServiceUnavailable,
+				Description: "{{if .Service}}{{.Service}}{{else}}服务{{end}}暂不可用",
+				Solution:    "请稍后重试",
+				ErrorLink:   "暂无",
+			},
+			"en-US": {
+				ErrorCode:   ServiceUnavailable,
+				Description: "{{if .Service}}{{.Service}}{{else}}Service{{end}} unavailable",
+				Solution:    "Try again later",
+				ErrorLink:   "None",
+			},
+		},
+		GatewayTimeout: {
+			"zh-CN": {
+				ErrorCode:   GatewayTimeout,
+				Description: "{{if .Service}}{{.Service}}{{else}}上游服务{{end}}响应超时",
+				Solution:    "请稍后重试",
+				ErrorLink:   "暂无",
+			},
+			"en-US": {
+				ErrorCode:   GatewayTimeout,
+				Description: "{{if .Service}}{{.Service}}{{else}}Upstream service{{end}} timed out",
+				Solution:    "Try again later",
+				ErrorLink:   "None",
+			},
+		},
+	}
+
+	// commonErrorTemplates 系统默认错误预编译的模板，错误码 -> 语言 -> 字段
+	commonErrorTemplates = mustParseCommonErrors()
+)
+
+var (
+	commonErrorDefinitions = []ErrorDefinition{
+		{Code: InternalError, HTTPStatus: http.StatusInternalServerError, Retryable: false, Severity: SeverityError, Category: "server"},
+		{Code: ValidationError, HTTPStatus: http.StatusBadRequest, Retryable: false, Severity: SeverityInfo, Category: "client"},
+		{Code: BadRequest, HTTPStatus: http.StatusBadRequest, Retryable: false, Severity: SeverityInfo, Category: "client"},
+		{Code: Unauthorized, HTTPStatus: http.StatusUnauthorized, Retryable: false, Severity: SeverityInfo, Category: "client"},
+		{Code: Forbidden, HTTPStatus: http.StatusForbidden, Retryable: false, Severity: SeverityInfo, Category: "client"},
+		{Code: NotFound, HTTPStatus: http.StatusNotFound, Retryable: false, Severity: SeverityInfo, Category: "client"},
+		{Code: Conflict, HTTPStatus: http.StatusConflict, Retryable: false, Severity: SeverityInfo, Category: "client"},
+		{Code: NotAcceptable, HTTPStatus: http.StatusNotAcceptable, Retryable: false, Severity: SeverityInfo, Category: "client"},
+		{Code: PayloadTooLarge, HTTPStatus: http.StatusRequestEntityTooLarge, Retryable: false, Severity: SeverityInfo, Category: "client"},
+		{Code: TooManyRequests, HTTPStatus: http.StatusTooManyRequests, Retryable: true, Severity: SeverityWarn, Category: "client"},
+		{Code: ServiceUnavailable, HTTPStatus: http.StatusServiceUnavailable, Retryable: true, Severity: SeverityError, Category: "server"},
+		{Code: GatewayTimeout, HTTPStatus: http.StatusGatewayTimeout, Retryable: true, Severity: SeverityError, Category: "server"},
 	}
 )
+
+func mustParseCommonErrors() map[string]map[string]map[string]*gotemplate.Template {
+	templates := make(map[string]map[string]map[string]*gotemplate.Template, len(commonErrorI18n))
+	for errorCode, langErrs := range commonErrorI18n {
+		templates[errorCode] = make(map[string]map[string]*gotemplate.Template, len(langErrs))
+		for lang, baseErr := range langErrs {
+			templates[errorCode][lang] = map[string]*gotemplate.Template{
+				"Description": gotemplate.Must(gotemplate.New(errorCode + ".Description").Parse(baseErr.Description)),
+				"Solution":    gotemplate.Must(gotemplate.New(errorCode + ".Solution").Parse(baseErr.Solution)),
+			}
+		}
+	}
+	return templates
+}
+
+// renderCommonError 渲染系统默认错误的 Description 或 Solution，错误码不是系统默认错误时返回 false。
+func renderCommonError(errorCode string, lang string, field string, templateData map[string]interface{}) (string, bool) {
+	tmpl, ok := commonErrorTemplates[errorCode][lang][field]
+	if !ok {
+		return "", false
+	}
+	if templateData == nil {
+		templateData = map[string]interface{}{}
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, templateData); err != nil {
+		return "", false
+	}
+	return buf.String(), true
+}
@@ -0,0 +1,140 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Severity 错误的日志级别。
+type Severity string
+
+const (
+	SeverityDebug Severity = "debug"
+	SeverityInfo  Severity = "info"
+	SeverityWarn  Severity = "warn"
+	SeverityError Severity = "error"
+)
+
+// ErrorDefinition 错误码定义，包含默认HTTP状态码和元数据。
+type ErrorDefinition struct {
+	Code       string   `toml:"code" json:"code"`               // 错误码
+	HTTPStatus int      `toml:"http_status" json:"http_status"` // 默认HTTP状态码
+	Retryable  bool     `toml:"retryable" json:"retryable"`     // 是否可重试
+	Severity   Severity `toml:"severity" json:"severity"`       // 日志级别
+	Category   string   `toml:"category" json:"category"`       // 分类
+}
+
+// errorDefinitionFile 错误码定义文件格式。
+type errorDefinitionFile struct {
+	Errors []ErrorDefinition `toml:"errors"`
+}
+
+// DefaultHTTPStatus 错误码未定义默认HTTP状态码时使用
+var DefaultHTTPStatus = http.StatusInternalServerError
+
+func (d ErrorDefinition) validate() error {
+	if d.Code == "" {
+		return fmt.Errorf("error definition missing code")
+	}
+	if d.HTTPStatus != 0 && (d.HTTPStatus < 100 || d.HTTPStatus > 599) {
+		return fmt.Errorf("errorCode %s has invalid http_status: %d", d.Code, d.HTTPStatus)
+	}
+	switch d.Severity {
+	case "", SeverityDebug, SeverityInfo, SeverityWarn, SeverityError:
+	default:
+		return fmt.Errorf("errorCode %s has invalid severity: %s", d.Code, d.Severity)
+	}
+	return nil
+}
+
+// LoadErrorDefinitions 从TOML文件读取错误码定义，格式为：
+//
+//	[[errors]]
+//	code = "NotFound"
+//	http_status = 404
+//	retryable = false
+//	severity = "info"
+//	category = "client"
+func LoadErrorDefinitions(filename string) ([]ErrorDefinition, error) {
+	buf, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("load error definition file %s failed: %v", filename, err)
+	}
+
+	var file errorDefinitionFile
+	if err = toml.Unmarshal(buf, &file); err != nil {
+		return nil, fmt.Errorf("unmarshal error definition file %s failed: %v", filename, err)
+	}
+	return file.Errors, nil
+}
+
+// RegisterDefinitions 注册错误码及其定义，校验失败时返回错误，此时不注册任何错误码。
+func (r *ErrorRegistry) RegisterDefinitions(defs []ErrorDefinition) error {
+	codes := make([]string, 0, len(defs))
+	for _, def := range defs {
+		if err := def.validate(); err != nil {
+			return err
+		}
+		codes = append(codes, def.Code)
+	}
+
+	if err := r.Register(codes); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, def := range defs {
+		r.defs[def.Code] = def
+	}
+	return nil
+}
+
+// Definition 获取错误码定义，未定义时返回 false。
+func (r *ErrorRegistry) Definition(errorCode string) (ErrorDefinition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	def, ok := r.defs[errorCode]
+	return def, ok
+}
+
+// NewHTTPErrorByCode 使用错误码定义的默认HTTP状态码创建 HTTPError。
+func (r *ErrorRegistry) NewHTTPErrorByCode(ctx context.Context, errorCode string) *HTTPError {
+	httpCode := DefaultHTTPStatus
+	if def, ok := r.Definition(errorCode); ok && def.HTTPStatus != 0 {
+		httpCode = def.HTTPStatus
+	}
+	return r.NewHTTPError(ctx, httpCode, errorCode)
+}
+
+// RegisterDefinitions 在 DefaultRegistry 中注册错误码及其定义，失败时退出进程。
+func RegisterDefinitions(defs []ErrorDefinition) {
+	if err := DefaultRegistry.RegisterDefinitions(defs); err != nil {
+		log.Fatalf(err.Error())
+	}
+}
+
+// RegisterDefinitionFile 从TOML文件读取错误码定义并注册到 DefaultRegistry，失败时退出进程。
+func RegisterDefinitionFile(filename string) {
+	defs, err := LoadErrorDefinitions(filename)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	RegisterDefinitions(defs)
+}
+
+// GetErrorDefinition 获取 DefaultRegistry 中的错误码定义。
+func GetErrorDefinition(errorCode string) (ErrorDefinition, bool) {
+	return DefaultRegistry.Definition(errorCode)
+}
+
+// NewHTTPErrorByCode 使用 DefaultRegistry 和错误码定义的默认HTTP状态码创建 HTTPError。
+func NewHTTPErrorByCode(ctx context.Context, errorCode string) *HTTPError {
+	return DefaultRegistry.NewHTTPErrorByCode(ctx, errorCode)
+}
@@ -0,0 +1,80 @@
+package rest_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/RockyRori/AdoLib/rest"
+	"github.com/RockyRori/AdoLib/rest/resttest"
+)
+
+func registerDefinitionFile(t *testing.T, registry *rest.ErrorRegistry, filename string) error {
+	t.Helper()
+
+	defs, err := rest.LoadErrorDefinitions(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return registry.RegisterDefinitions(defs)
+}
+
+func TestRegisterDefinitions(t *testing.T) {
+	resttest.LoadLocale(t, "testdata/locale")
+	registry := rest.NewErrorRegistry()
+	if err := registerDefinitionFile(t, registry, "testdata/definitions/errors.toml"); err != nil {
+		t.Fatal(err)
+	}
+
+	def, ok := registry.Definition("DemoQuotaExceeded")
+	want := rest.ErrorDefinition{Code: "DemoQuotaExceeded", Retryable: true, Severity: rest.SeverityWarn}
+	if !ok || def != want {
+		t.Errorf("Definition = %+v, %v, want %+v", def, ok, want)
+	}
+
+	ctx := context.WithValue(context.Background(), rest.XLangKey, "en-US")
+	tests := []struct {
+		errorCode string
+		status    int
+	}{
+		{errorCode: "DemoUserNotFound", status: http.StatusNotFound},
+		// 未定义状态码时使用 DefaultHTTPStatus
+		{errorCode: "DemoQuotaExceeded", status: rest.DefaultHTTPStatus},
+		{errorCode: rest.TooManyRequests, status: http.StatusTooManyRequests},
+	}
+	for _, tt := range tests {
+		if got := registry.NewHTTPErrorByCode(ctx, tt.errorCode).HTTPCode; got != tt.status {
+			t.Errorf("NewHTTPErrorByCode(%s) status = %d, want %d", tt.errorCode, got, tt.status)
+		}
+	}
+}
+
+func TestRegisterDefinitionsInvalid(t *testing.T) {
+	resttest.LoadLocale(t, "testdata/locale")
+
+	for _, filename := range []string{
+		"testdata/definitions/invalid_status.toml",
+		"testdata/definitions/invalid_severity.toml",
+		// DemoMissingTranslation 没有翻译，整批都不注册
+		"testdata/definitions/partial.toml",
+	} {
+		registry := rest.NewErrorRegistry()
+		if err := registerDefinitionFile(t, registry, filename); err == nil {
+			t.Errorf("%s: RegisterDefinitions should fail", filename)
+		}
+		if _, ok := registry.Lookup("DemoUserNotFound", "en-US"); ok {
+			t.Errorf("%s: a failed batch should not register any code", filename)
+		}
+		if _, ok := registry.Definition("DemoUserNotFound"); ok {
+			t.Errorf("%s: a failed batch should not register any definition", filename)
+		}
+	}
+
+	registry := rest.NewErrorRegistry()
+	if err := registry.RegisterDefinitions([]rest.ErrorDefinition{{HTTPStatus: http.StatusNotFound}}); err == nil {
+		t.Error("a definition without code should be rejected")
+	}
+	if _, err := rest.LoadErrorDefinitions("testdata/definitions/missing.toml"); err == nil {
+		t.Error("LoadErrorDefinitions should fail on a missing file")
+	}
+}
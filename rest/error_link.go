@@ -0,0 +1,73 @@
+package rest
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strings"
+	"sync/atomic"
+	gotemplate "text/template"
+)
+
+// DefaultErrorLinkTemplate 默认的错误链接模板
+const DefaultErrorLinkTemplate = "{{.BaseURL}}/{{.Lang}}/errors/{{.Code}}"
+
+// errorLinkConfig 错误链接配置。
+type errorLinkConfig struct {
+	baseURL string
+	tmpl    *gotemplate.Template
+}
+
+var (
+	// NoErrorLinks 视为没有显式错误链接的值
+	NoErrorLinks = map[string]bool{
+		"":     true,
+		"暂无":   true,
+		"None": true,
+	}
+
+	errorLink atomic.Pointer[errorLinkConfig]
+)
+
+// SetErrorLink 设置文档站点地址和错误链接模板，错误码没有显式链接时按模板生成。
+// 模板参数为 BaseURL、Lang、Code，linkTemplate 为空时使用 DefaultErrorLinkTemplate。
+func SetErrorLink(baseURL string, linkTemplate string) error {
+	if linkTemplate == "" {
+		linkTemplate = DefaultErrorLinkTemplate
+	}
+
+	tmpl, err := gotemplate.New("ErrorLink").Option("missingkey=error").Parse(linkTemplate)
+	if err != nil {
+		return fmt.Errorf("invalid error link template %s: %v", linkTemplate, err)
+	}
+
+	errorLink.Store(&errorLinkConfig{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		tmpl:    tmpl,
+	})
+	return nil
+}
+
+// resolveErrorLink 错误码有显式链接时返回原链接，否则按模板生成，未设置模板时返回原链接。
+func resolveErrorLink(errorCode string, lang string, link string) string {
+	if !NoErrorLinks[link] {
+		return link
+	}
+
+	cfg := errorLink.Load()
+	if cfg == nil {
+		return link
+	}
+
+	var buf bytes.Buffer
+	err := cfg.tmpl.Execute(&buf, map[string]interface{}{
+		"BaseURL": cfg.baseURL,
+		"Lang":    lang,
+		"Code":    errorCode,
+	})
+	if err != nil {
+		log.Printf("render error link of errorCode %s failed: %v", errorCode, err)
+		return link
+	}
+	return buf.String()
+}
@@ -0,0 +1,37 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestSetErrorLink(t *testing.T) {
+	old := errorLink.Load()
+	t.Cleanup(func() { errorLink.Store(old) })
+
+	if err := SetErrorLink("https://docs.example.com/", ""); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.WithValue(context.Background(), XLangKey, "en-US")
+	e := NewHTTPError(ctx, http.StatusNotFound, NotFound)
+	if want := "https://docs.example.com/en-US/errors/NotFound"; e.BaseError.ErrorLink != want {
+		t.Errorf("error_link = %q, want %q", e.BaseError.ErrorLink, want)
+	}
+
+	if err := SetErrorLink("https://docs.example.com", "{{.BaseURL}}/errors/{{.Code}}?lang={{.Lang}}"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := resolveErrorLink(NotFound, "zh-CN", "暂无"), "https://docs.example.com/errors/NotFound?lang=zh-CN"; got != want {
+		t.Errorf("error_link = %q, want %q", got, want)
+	}
+
+	// 显式链接保持不变
+	if got, want := resolveErrorLink(NotFound, "zh-CN", "https://wiki.example.com/404"), "https://wiki.example.com/404"; got != want {
+		t.Errorf("error_link = %q, want %q", got, want)
+	}
+
+	if err := SetErrorLink("https://docs.example.com", "{{.BaseURL"); err == nil {
+		t.Error("SetErrorLink should reject an invalid template")
+	}
+}
@@ -4,18 +4,21 @@ import (
 	"context"
 	"encoding/json"
 	"log"
+	"time"
 
 	. "github.com/RockyRori/AdoLib/i18n"
 )
 
 type BaseError struct {
-	ErrorCode               string                 `json:"error_code"`    // 错误码
-	Description             string                 `json:"description"`   // 错误描述
-	Solution                string                 `json:"solution"`      // 解决方法
-	ErrorLink               string                 `json:"error_link"`    // 错误链接
-	ErrorDetails            interface{}            `json:"error_details"` // 详细内容
-	DescriptionTemplateData map[string]interface{} `json:"-"`             // 错误描述参数
-	SolutionTemplateData    map[string]interface{} `json:"-"`             // 解决方法参数
+	ErrorCode               string                 `json:"error_code"`           // 错误码
+	Description             string                 `json:"description"`          // 错误描述
+	Solution                string                 `json:"solution"`             // 解决方法
+	ErrorLink               string                 `json:"error_link"`           // 错误链接
+	ErrorDetails            interface{}            `json:"error_details"`        // 详细内容
+	RequestID               string                 `json:"request_id,omitempty"` // 请求ID
+	TraceID                 string                 `json:"trace_id,omitempty"`   // trace ID
+	DescriptionTemplateData map[string]interface{} `json:"-"`                    // 错误描述参数
+	SolutionTemplateData    map[string]interface{} `json:"-"`                    // 解决方法参数
 }
 
 var (
@@ -25,8 +28,6 @@ var (
 		"en-US": "en-US",
 	}
 	DefaultLanguage = "zh-CN"
-
-	allErrs = commonErrorI18n
 )
 
 // SetLang 设置语言
@@ -38,79 +39,125 @@ func SetLang(langStr string) {
 	DefaultLanguage = langStr
 }
 
+// Register 在 DefaultRegistry 中注册错误码，失败时退出进程。
 func Register(errorCodeList []string) {
-	for _, errorCode := range errorCodeList {
-		if _, ok := allErrs[errorCode]; ok {
-			log.Fatalf("duplicate errorCode: %s", errorCode)
-		}
-		allErrs[errorCode] = make(map[string]BaseError)
-		for lang := range Languages {
-			allErrs[errorCode][lang] = BaseError{
-				ErrorCode:               errorCode,
-				Description:             Translate(lang, errorCode+".Description", nil),
-				Solution:                Translate(lang, errorCode+".Solution", nil),
-				ErrorLink:               Translate(lang, errorCode+".ErrorLink", nil),
-				ErrorDetails:            "",
-				DescriptionTemplateData: make(map[string]interface{}),
-				SolutionTemplateData:    make(map[string]interface{}),
-			}
-		}
+	if err := DefaultRegistry.Register(errorCodeList); err != nil {
+		log.Fatalf(err.Error())
+	}
+}
+
+// SetNamingScheme 设置 DefaultRegistry 的错误码命名规则，失败时退出进程。
+func SetNamingScheme(scheme NamingScheme) {
+	if err := DefaultRegistry.SetNamingScheme(scheme); err != nil {
+		log.Fatalf(err.Error())
 	}
 }
 
 type HTTPError struct {
 	HTTPCode  int
 	Language  string
+	Tenant    string
+	Location  *time.Location
 	BaseError BaseError
+
+	// 底层错误
+	cause error
+	// 创建错误的注册表，用于重新渲染
+	registry *ErrorRegistry
+	// 是否延迟本地化
+	lazy bool
 }
 
-// NewHTTPError 创建 HTTPError。
-func NewHTTPError(ctx context.Context, httpCode int, errorCode string) *HTTPError {
-	lang := GetLanguageByCtx(ctx)
+// Code 错误码，用于 errors.Is(err, rest.Code("NotFound")) 按错误码匹配 HTTPError。
+type Code string
 
-	errs, ok := allErrs[errorCode]
-	if !ok {
-		log.Fatalf("missing errorCode: %s", errorCode)
-		return nil
-	}
-	err := errs[lang]
-	if !ok {
-		log.Fatalf("errorCode %s missing lang: %s", errorCode, lang)
-		return nil
-	}
+func (c Code) Error() string {
+	return string(c)
+}
 
-	return &HTTPError{
-		HTTPCode: httpCode,
-		Language: lang,
-		BaseError: BaseError{
-			ErrorCode:    errorCode,
-			Description:  err.Description,
-			ErrorLink:    err.ErrorLink,
-			Solution:     err.Solution,
-			ErrorDetails: err.ErrorDetails,
-		},
-	}
+// NewHTTPError 使用 DefaultRegistry 创建 HTTPError。
+func NewHTTPError(ctx context.Context, httpCode int, errorCode string) *HTTPError {
+	return DefaultRegistry.NewHTTPError(ctx, httpCode, errorCode)
 }
 
 func (e *HTTPError) WithDescription(templateData map[string]interface{}) *HTTPError {
 	e.BaseError.DescriptionTemplateData = templateData
-	e.BaseError.Description = Translate(e.Language, e.BaseError.ErrorCode+".Description", templateData)
+	if e.lazy {
+		return e
+	}
+	e.BaseError.Description = e.translate("Description", templateData)
 	return e
 }
 
 func (e *HTTPError) WithSolution(templateData map[string]interface{}) *HTTPError {
 	e.BaseError.SolutionTemplateData = templateData
-	e.BaseError.Solution = Translate(e.Language, e.BaseError.ErrorCode+".Solution", templateData)
+	if e.lazy {
+		return e
+	}
+	e.BaseError.Solution = e.translate("Solution", templateData)
 	return e
 }
 
+// translate 渲染错误的 Description 或 Solution，文案来源与 NewHTTPError 一致：
+// 优先使用租户覆盖，其次系统默认错误的文案（未被语言文件覆盖时），最后使用语言文件。
+func (e *HTTPError) translate(field string, templateData map[string]interface{}) string {
+	messageId := e.BaseError.ErrorCode + "." + field
+	data := InLocation(templateData, e.Location)
+
+	registry := e.registry
+	if registry == nil {
+		registry = DefaultRegistry
+	}
+	if registry.isBuiltin(e.BaseError.ErrorCode) && !HasTenantMessage(e.Tenant, e.Language, messageId) {
+		if text, ok := renderCommonError(e.BaseError.ErrorCode, e.Language, field, data); ok {
+			return text
+		}
+	}
+
+	text, err := TranslateTenantWithError(e.Tenant, e.Language, messageId, data)
+	if err == nil {
+		return text
+	}
+	if text, ok := renderCommonError(e.BaseError.ErrorCode, e.Language, field, data); ok {
+		return text
+	}
+
+	log.Fatalf(err.Error())
+	return ""
+}
+
 // WithErrorDetails 设置错误详情。
 func (e *HTTPError) WithErrorDetails(errorDetails interface{}) *HTTPError {
 	e.BaseError.ErrorDetails = errorDetails
 	return e
 }
 
+// Wrap 设置底层错误，可通过 errors.Unwrap 获取。
+func (e *HTTPError) Wrap(cause error) *HTTPError {
+	e.cause = cause
+	return e
+}
+
+// Unwrap 返回底层错误。
+func (e *HTTPError) Unwrap() error {
+	return e.cause
+}
+
+// Is 支持 errors.Is 按错误码匹配。
+func (e *HTTPError) Is(target error) bool {
+	switch t := target.(type) {
+	case Code:
+		return e.BaseError.ErrorCode == string(t)
+	case *HTTPError:
+		return t != nil && e.BaseError.ErrorCode == t.BaseError.ErrorCode
+	}
+	return false
+}
+
 func (e *HTTPError) Error() string {
+	if e.lazy {
+		return e.Localize(context.Background()).Error()
+	}
 	errStr, _ := json.Marshal(e.BaseError)
 	return string(errStr)
 }
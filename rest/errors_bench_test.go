@@ -0,0 +1,37 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/RockyRori/AdoLib/i18n"
+)
+
+func BenchmarkNewHTTPError(b *testing.B) {
+	restore := i18n.Reset()
+	b.Cleanup(restore)
+	i18n.RegisterI18n("testdata/locale")
+
+	registry := NewErrorRegistry()
+	if err := registry.Register([]string{"DemoUserNotFound"}); err != nil {
+		b.Fatal(err)
+	}
+	ctx := context.WithValue(context.Background(), XLangKey, "en-US")
+
+	b.Run("common", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			registry.NewHTTPError(ctx, http.StatusNotFound, NotFound).
+				WithDescription(map[string]interface{}{"Resource": "user"})
+		}
+	})
+
+	b.Run("registered", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			registry.NewHTTPError(ctx, http.StatusNotFound, "DemoUserNotFound").
+				WithDescription(map[string]interface{}{"Name": "rocky"})
+		}
+	})
+}
@@ -0,0 +1,58 @@
+package rest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/RockyRori/AdoLib/rest"
+	"github.com/RockyRori/AdoLib/rest/resttest"
+	"github.com/gin-gonic/gin"
+)
+
+func TestHTTPErrorWrap(t *testing.T) {
+	cause := errors.New("record not found")
+	ctx := context.WithValue(context.Background(), rest.XLangKey, "en-US")
+	e := rest.NewHTTPError(ctx, http.StatusNotFound, rest.NotFound).Wrap(cause)
+
+	if !errors.Is(e, cause) {
+		t.Error("errors.Is should find the cause")
+	}
+	if errors.Unwrap(e) != cause {
+		t.Error("Unwrap should return the cause")
+	}
+	if !errors.Is(e, rest.Code(rest.NotFound)) {
+		t.Error("errors.Is should match the error code")
+	}
+	if errors.Is(e, rest.Code(rest.Conflict)) {
+		t.Error("errors.Is should not match another error code")
+	}
+	if !errors.Is(e, rest.NewHTTPError(ctx, http.StatusNotFound, rest.NotFound)) {
+		t.Error("errors.Is should match an HTTPError with the same code")
+	}
+
+	wrapped := fmt.Errorf("get user: %w", e)
+	var httpErr *rest.HTTPError
+	if !errors.As(wrapped, &httpErr) || httpErr != e {
+		t.Error("errors.As should find the HTTPError in the chain")
+	}
+}
+
+func TestReplyErrorWrapped(t *testing.T) {
+	engine := resttest.NewEngine(resttest.Route{
+		Method: http.MethodGet,
+		Path:   "/users/:id",
+		Handlers: []gin.HandlerFunc{func(c *gin.Context) {
+			err := rest.NewHTTPError(rest.GetLanguageCtx(c), http.StatusNotFound, rest.NotFound).
+				WithDescription(map[string]interface{}{"Resource": "user"})
+			rest.ReplyError(c, fmt.Errorf("service: %w", err))
+		}},
+	})
+
+	resttest.Do(t, engine, resttest.Request{Method: http.MethodGet, Path: "/users/1", Language: "en-US"}).
+		AssertStatus(t, http.StatusNotFound).
+		AssertErrorCode(t, rest.NotFound).
+		AssertDescription(t, "user not found")
+}
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"io"
 	"log"
+	"mime"
 	"net/http"
 	"net/url"
 	"time"
@@ -34,12 +35,15 @@ type HTTPClient interface {
 
 // httpClient HTTP客户端结构。
 type httpClient struct {
-	client *http.Client
+	client          *http.Client
+	decodeHTTPError bool
 }
 
 // HttpClientOptions httpClient 配置信息。
 type HttpClientOptions struct {
 	TimeOut int
+	// DecodeHTTPError 为 true 时，4xx/5xx 响应体为 BaseError 或 problem+json 格式时返回 *HTTPError
+	DecodeHTTPError bool
 }
 
 // NewRawHTTPClient 创建原生HTTP客户端对象。
@@ -53,7 +57,8 @@ func NewRawHTTPClient() *http.Client {
 // NewHTTPClientWithOptions 根据配置创建HTTP客户端对象。
 func NewHTTPClientWithOptions(opts HttpClientOptions) HTTPClient {
 	client := &httpClient{
-		client: NewRawHTTPClientWithOptions(opts),
+		client:          NewRawHTTPClientWithOptions(opts),
+		decodeHTTPError: opts.DecodeHTTPError,
 	}
 
 	return client
@@ -154,7 +159,7 @@ func (c *httpClient) PatchNoUnmarshal(ctx context.Context, url string, headers m
 func (c *httpClient) httpDo(ctx context.Context, mtehod string, url string, headers map[string]string,
 	reqParam interface{}) (respCode int, respData interface{}, err error) {
 
-	respCode, respBody, err := c.httpDoNoUnmarshal(ctx, mtehod, url, headers, reqParam)
+	respCode, respHeader, respBody, err := c.httpDoRaw(ctx, mtehod, url, headers, reqParam)
 	if err != nil {
 		log.Println(err.Error())
 		return
@@ -166,22 +171,93 @@ func (c *httpClient) httpDo(ctx context.Context, mtehod string, url string, head
 	err = json.Unmarshal(respBody, &respData)
 	if err != nil {
 		log.Println(err.Error())
+		return
+	}
+
+	if c.decodeHTTPError && respCode >= http.StatusBadRequest {
+		if httpErr := decodeHTTPError(ctx, respCode, respHeader.Get(ContentTypeKey), respData); httpErr != nil {
+			err = httpErr
+		}
 	}
 	return
 }
 
+// decodeHTTPError 将 BaseError 或 problem+json 格式的错误响应转换为 HTTPError，格式不符时返回 nil。
+// 只有 Content-Type 为 application/problem+json 的响应按 problem+json 解析。
+func decodeHTTPError(ctx context.Context, respCode int, contentType string, respData interface{}) *HTTPError {
+	fields, ok := respData.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	str := func(k string) string {
+		v, _ := fields[k].(string)
+		return v
+	}
+
+	httpErr := &HTTPError{
+		HTTPCode: respCode,
+		Language: GetLanguageByCtx(ctx),
+		Tenant:   GetTenantByCtx(ctx),
+		Location: GetLocationByCtx(ctx),
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == ContentTypeProblemJson:
+		// error_code 等扩展字段存在时优先使用；type 只作为错误链接，没有 error_code 时错误码为空
+		errorLink := str("error_link")
+		if errorLink == "" && str("type") != "about:blank" {
+			errorLink = str("type")
+		}
+		errorDetails := fields["error_details"]
+		if errorDetails == nil && str("detail") != "" {
+			errorDetails = str("detail")
+		}
+		httpErr.BaseError = BaseError{
+			ErrorCode:    str("error_code"),
+			Description:  str("title"),
+			Solution:     str("solution"),
+			ErrorLink:    errorLink,
+			ErrorDetails: errorDetails,
+		}
+
+	case str("error_code") != "" && str("description") != "":
+		httpErr.BaseError = BaseError{
+			ErrorCode:    str("error_code"),
+			Description:  str("description"),
+			Solution:     str("solution"),
+			ErrorLink:    str("error_link"),
+			ErrorDetails: fields["error_details"],
+		}
+
+	default:
+		return nil
+	}
+
+	return httpErr
+}
+
 // 返回原始respBody, 不进行反序列化。
 func (c *httpClient) httpDoNoUnmarshal(ctx context.Context, mtehod string, url string, headers map[string]string,
 	reqParam interface{}) (respCode int, respBody []byte, err error) {
 
+	respCode, _, respBody, err = c.httpDoRaw(ctx, mtehod, url, headers, reqParam)
+	return
+}
+
+// 返回原始respBody和响应header。
+func (c *httpClient) httpDoRaw(ctx context.Context, mtehod string, url string, headers map[string]string,
+	reqParam interface{}) (respCode int, respHeader http.Header, respBody []byte, err error) {
+
 	if c.client == nil {
-		return 0, nil, errors.New("http client is unavailable")
+		return 0, nil, nil, errors.New("http client is unavailable")
 	}
 
 	req, err := c.generateReq(ctx, mtehod, url, headers, reqParam)
 	if err != nil {
 		log.Println(err.Error())
-		return 0, nil, err
+		return 0, nil, nil, err
 	}
 
 	// 把 trace 上下文注入到请求的 header 中
@@ -200,6 +276,7 @@ func (c *httpClient) httpDoNoUnmarshal(ctx context.Context, mtehod string, url s
 	}()
 	respBody, err = io.ReadAll(resp.Body)
 	respCode = resp.StatusCode
+	respHeader = resp.Header
 	return
 }
 
@@ -0,0 +1,77 @@
+package rest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/RockyRori/AdoLib/rest"
+)
+
+func TestHTTPClientDecodeHTTPError(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        *rest.BaseError // nil 表示不解析为 HTTPError
+	}{
+		{
+			name:        "base error",
+			contentType: rest.ContentTypeJson,
+			body:        `{"error_code":"NotFound","description":"user not found","solution":"check","error_link":"None"}`,
+			want:        &rest.BaseError{ErrorCode: "NotFound", Description: "user not found", Solution: "check", ErrorLink: "None"},
+		},
+		{
+			name:        "problem with extension",
+			contentType: rest.ContentTypeProblemJson,
+			body:        `{"type":"https://example.com/errors/NotFound","title":"user not found","status":404,"error_code":"NotFound"}`,
+			want:        &rest.BaseError{ErrorCode: "NotFound", Description: "user not found", ErrorLink: "https://example.com/errors/NotFound"},
+		},
+		{
+			name:        "problem without error code",
+			contentType: rest.ContentTypeProblemJson + "; charset=utf-8",
+			body:        `{"type":"about:blank","title":"Not Found","status":404,"detail":"no such user"}`,
+			want:        &rest.BaseError{Description: "Not Found", ErrorDetails: "no such user"},
+		},
+		{
+			name:        "problem shape without problem content type",
+			contentType: rest.ContentTypeJson,
+			body:        `{"type":"about:blank","title":"Not Found","status":404}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(rest.ContentTypeKey, tt.contentType)
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := rest.NewHTTPClientWithOptions(rest.HttpClientOptions{TimeOut: 5, DecodeHTTPError: true})
+			_, _, err := client.Get(context.Background(), server.URL, nil, nil)
+
+			var httpErr *rest.HTTPError
+			if !errors.As(err, &httpErr) {
+				if tt.want != nil {
+					t.Fatalf("Get err = %v, want *HTTPError", err)
+				}
+				return
+			}
+			if tt.want == nil {
+				t.Fatalf("Get err = %v, want nil", err)
+			}
+			if httpErr.HTTPCode != http.StatusNotFound {
+				t.Errorf("HTTPCode = %d, want %d", httpErr.HTTPCode, http.StatusNotFound)
+			}
+			got := httpErr.BaseError
+			if got.ErrorCode != tt.want.ErrorCode || got.Description != tt.want.Description ||
+				got.Solution != tt.want.Solution || got.ErrorLink != tt.want.ErrorLink || got.ErrorDetails != tt.want.ErrorDetails {
+				t.Errorf("BaseError = %+v, want %+v", got, *tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package rest
+
+import (
+	"context"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/language"
+)
+
+// LanguageResolver 从请求中解析语言，无法解析时返回空字符串。
+type LanguageResolver func(c *gin.Context) string
+
+// HeaderResolver 从指定header解析语言，例如 X-Language。
+func HeaderResolver(header string) LanguageResolver {
+	return func(c *gin.Context) string {
+		return c.GetHeader(header)
+	}
+}
+
+// AcceptLanguageResolver 从 Accept-Language 解析语言，按 q 值选择支持的语言。
+func AcceptLanguageResolver() LanguageResolver {
+	return func(c *gin.Context) string {
+		return c.GetHeader("Accept-Language")
+	}
+}
+
+// CookieResolver 从指定cookie解析语言。
+func CookieResolver(name string) LanguageResolver {
+	return func(c *gin.Context) string {
+		value, err := c.Cookie(name)
+		if err != nil {
+			return ""
+		}
+		return value
+	}
+}
+
+// QueryResolver 从指定查询参数解析语言，例如 ?lang=en-US。
+func QueryResolver(param string) LanguageResolver {
+	return func(c *gin.Context) string {
+		return c.Query(param)
+	}
+}
+
+// DefaultLanguageResolvers 未指定解析器时使用的解析顺序
+var DefaultLanguageResolvers = []LanguageResolver{
+	HeaderResolver(XLangHeader),
+	AcceptLanguageResolver(),
+}
+
+// LanguageMiddleware 按顺序使用解析器获取第一个支持的语言，写入请求的 context，
+// 之后 GetLanguageCtx 直接使用该语言。resolvers 为空时使用 DefaultLanguageResolvers。
+// 自定义解析器可以读取用户资料或 JWT 中的语言偏好。
+func LanguageMiddleware(resolvers ...LanguageResolver) gin.HandlerFunc {
+	if len(resolvers) == 0 {
+		resolvers = DefaultLanguageResolvers
+	}
+
+	return func(c *gin.Context) {
+		for _, resolve := range resolvers {
+			if lang := matchLanguage(resolve(c)); lang != "" {
+				c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), XLangKey, lang))
+				break
+			}
+		}
+		c.Next()
+	}
+}
+
+// matchLanguage 将语言（支持 Accept-Language 格式）匹配为 Languages 中的语言，无法匹配时返回空字符串。
+func matchLanguage(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	if _, ok := Languages[raw]; ok {
+		return raw
+	}
+
+	tags, _, err := language.ParseAcceptLanguage(raw)
+	if err != nil || len(tags) == 0 {
+		return ""
+	}
+
+	supported := make([]string, 0, len(Languages))
+	for lang := range Languages {
+		supported = append(supported, lang)
+	}
+	sort.Strings(supported)
+
+	supportedTags := make([]language.Tag, 0, len(supported))
+	for _, lang := range supported {
+		supportedTags = append(supportedTags, language.Make(lang))
+	}
+
+	_, index, confidence := language.NewMatcher(supportedTags).Match(tags...)
+	if confidence == language.No {
+		return ""
+	}
+	return supported[index]
+}
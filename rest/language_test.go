@@ -0,0 +1,63 @@
+package rest_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/RockyRori/AdoLib/rest"
+	"github.com/RockyRori/AdoLib/rest/resttest"
+	"github.com/gin-gonic/gin"
+)
+
+func TestLanguageMiddleware(t *testing.T) {
+	profileResolver := func(c *gin.Context) string {
+		return c.GetHeader("X-Profile-Language")
+	}
+	handler := func(c *gin.Context) {
+		c.String(http.StatusOK, rest.GetLanguageByCtx(rest.GetLanguageCtx(c)))
+	}
+	engine := resttest.NewEngine(
+		resttest.Route{
+			Method:   http.MethodGet,
+			Path:     "/default",
+			Handlers: []gin.HandlerFunc{rest.LanguageMiddleware(), handler},
+		},
+		resttest.Route{
+			Method: http.MethodGet,
+			Path:   "/chain",
+			Handlers: []gin.HandlerFunc{rest.LanguageMiddleware(
+				rest.QueryResolver("lang"),
+				rest.CookieResolver("lang"),
+				profileResolver,
+				rest.AcceptLanguageResolver(),
+			), handler},
+		},
+	)
+
+	tests := []struct {
+		name    string
+		path    string
+		headers map[string]string
+		want    string
+	}{
+		{name: "default language", path: "/default", want: rest.DefaultLanguage},
+		{name: "header", path: "/default", headers: map[string]string{rest.XLangHeader: "en-US"}, want: "en-US"},
+		{name: "accept language", path: "/default", headers: map[string]string{"Accept-Language": "fr;q=0.9, en;q=0.8"}, want: "en-US"},
+		{name: "header before accept language", path: "/default",
+			headers: map[string]string{rest.XLangHeader: "en-US", "Accept-Language": "zh-CN"}, want: "en-US"},
+		{name: "query", path: "/chain?lang=en-US", headers: map[string]string{"Cookie": "lang=zh-CN"}, want: "en-US"},
+		{name: "cookie", path: "/chain", headers: map[string]string{"Cookie": "lang=en-US"}, want: "en-US"},
+		{name: "unsupported query falls through", path: "/chain?lang=fr",
+			headers: map[string]string{"X-Profile-Language": "en"}, want: "en-US"},
+		{name: "custom resolver", path: "/chain", headers: map[string]string{"X-Profile-Language": "en-US"}, want: "en-US"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := resttest.Do(t, engine, resttest.Request{Method: http.MethodGet, Path: tt.path, Headers: tt.headers})
+			resp.AssertStatus(t, http.StatusOK)
+			if got := resp.Body.String(); got != tt.want {
+				t.Errorf("language = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
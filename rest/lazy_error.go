@@ -0,0 +1,83 @@
+package rest
+
+import (
+	"context"
+	"log"
+	"net/http"
+)
+
+// NewLazyHTTPError 使用 DefaultRegistry 创建延迟本地化的 HTTPError。
+func NewLazyHTTPError(httpCode int, errorCode string) *HTTPError {
+	return DefaultRegistry.NewLazyHTTPError(httpCode, errorCode)
+}
+
+// NewLazyHTTPError 创建延迟本地化的 HTTPError，只记录错误码和模板参数，
+// 在 ReplyError 时按请求语言渲染，Error() 按默认语言渲染，也可通过 Localize 按指定语言渲染。
+// 适用于后台任务等创建错误时还不知道语言的场景。错误码未注册时与 NewHTTPError 一样按 UnknownCodeBehavior 处理。
+func (r *ErrorRegistry) NewLazyHTTPError(httpCode int, errorCode string) *HTTPError {
+	r.mu.RLock()
+	_, ok := r.errs[errorCode]
+	behavior := r.unknownCodeBehavior
+	r.mu.RUnlock()
+
+	if !ok {
+		if behavior == UnknownCodeFatal {
+			log.Fatalf("missing errorCode: %s", errorCode)
+			return nil
+		}
+
+		log.Printf("missing errorCode: %s, fallback to %s", errorCode, InternalError)
+		return r.NewLazyHTTPError(http.StatusInternalServerError, InternalError).
+			WithErrorDetails(map[string]interface{}{"unknown_error_code": errorCode})
+	}
+
+	return &HTTPError{
+		HTTPCode: httpCode,
+		BaseError: BaseError{
+			ErrorCode: errorCode,
+		},
+		registry: r,
+		lazy:     true,
+	}
+}
+
+// IsLazy 是否为延迟本地化的 HTTPError。
+func (e *HTTPError) IsLazy() bool {
+	return e.lazy
+}
+
+// Localize 按 ctx 中的语言、租户和时区重新渲染错误，返回新的 HTTPError，原错误不变。
+// 同一个错误可以分别渲染给不同的受众，例如响应使用请求语言，审计日志使用 zh-CN。
+func (e *HTTPError) Localize(ctx context.Context) *HTTPError {
+	if e.registry == nil {
+		// 非注册表创建的错误（例如下游服务返回的错误）无法重新渲染
+		localized := *e
+		localized.lazy = false
+		return &localized
+	}
+
+	localized := e.registry.NewHTTPError(ctx, e.HTTPCode, e.BaseError.ErrorCode)
+	localized.cause = e.cause
+	localized.BaseError.RequestID = e.BaseError.RequestID
+	localized.BaseError.TraceID = e.BaseError.TraceID
+	if localized.BaseError.ErrorCode != e.BaseError.ErrorCode {
+		// 错误码未注册，已按 UnknownCodeBehavior 回退
+		return localized
+	}
+
+	if len(e.BaseError.DescriptionTemplateData) > 0 {
+		localized.WithDescription(e.BaseError.DescriptionTemplateData)
+	}
+	if len(e.BaseError.SolutionTemplateData) > 0 {
+		localized.WithSolution(e.BaseError.SolutionTemplateData)
+	}
+	if e.BaseError.ErrorDetails != nil {
+		localized.BaseError.ErrorDetails = e.BaseError.ErrorDetails
+	}
+	return localized
+}
+
+// LocalizeLang 按指定语言重新渲染错误，返回新的 HTTPError，原错误不变。
+func (e *HTTPError) LocalizeLang(lang string) *HTTPError {
+	return e.Localize(context.WithValue(context.Background(), XLangKey, lang))
+}
@@ -0,0 +1,69 @@
+package rest_test
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/RockyRori/AdoLib/rest"
+	"github.com/RockyRori/AdoLib/rest/resttest"
+	"github.com/gin-gonic/gin"
+)
+
+func TestLazyHTTPErrorLocalize(t *testing.T) {
+	e := rest.NewLazyHTTPError(http.StatusNotFound, rest.NotFound).
+		WithDescription(map[string]interface{}{"Resource": "order"})
+	if !e.IsLazy() {
+		t.Fatal("NewLazyHTTPError should create a lazy error")
+	}
+
+	for lang, want := range map[string]string{
+		"zh-CN": "order不存在",
+		"en-US": "order not found",
+	} {
+		localized := e.LocalizeLang(lang)
+		if localized.IsLazy() {
+			t.Errorf("LocalizeLang(%s) should not be lazy", lang)
+		}
+		if localized.BaseError.Description != want {
+			t.Errorf("LocalizeLang(%s) description = %q, want %q", lang, localized.BaseError.Description, want)
+		}
+	}
+	if e.BaseError.Description != "" {
+		t.Errorf("Localize should not change the lazy error, description = %q", e.BaseError.Description)
+	}
+}
+
+func TestLazyHTTPErrorReply(t *testing.T) {
+	engine := resttest.NewEngine(resttest.Route{
+		Method: http.MethodGet,
+		Path:   "/orders/1",
+		Handlers: []gin.HandlerFunc{func(c *gin.Context) {
+			err := rest.NewLazyHTTPError(http.StatusNotFound, rest.NotFound).
+				WithDescription(map[string]interface{}{"Resource": "order"})
+			rest.ReplyError(c, err)
+		}},
+	})
+
+	resttest.Do(t, engine, resttest.Request{Method: http.MethodGet, Path: "/orders/1", Language: "en-US"}).
+		AssertStatus(t, http.StatusNotFound).
+		AssertErrorCode(t, rest.NotFound).
+		AssertDescription(t, "order not found")
+}
+
+func TestLazyHTTPErrorUnknownCode(t *testing.T) {
+	registry := rest.NewErrorRegistry()
+	registry.SetUnknownCodeBehavior(rest.UnknownCodeFallback)
+
+	e := registry.NewLazyHTTPError(http.StatusNotFound, "MissingCode")
+	if e.HTTPCode != http.StatusInternalServerError || !errors.Is(e, rest.Code(rest.InternalError)) {
+		t.Fatalf("unknown code should fall back to 500 %s, got %d %s", rest.InternalError, e.HTTPCode, e.BaseError.ErrorCode)
+	}
+
+	localized := e.LocalizeLang("en-US")
+	want := map[string]interface{}{"unknown_error_code": "MissingCode"}
+	if !reflect.DeepEqual(localized.BaseError.ErrorDetails, want) {
+		t.Errorf("error_details = %v, want %v", localized.BaseError.ErrorDetails, want)
+	}
+}
@@ -0,0 +1,341 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	. "github.com/RockyRori/AdoLib/i18n"
+)
+
+var (
+	localizedStringType = reflect.TypeOf(LocalizedString{})
+	jsonMarshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+	// 类型是否包含 LocalizedString 的缓存
+	localizedTypeCache sync.Map
+	// 结构体类型的 JSON 字段缓存
+	structFieldsCache sync.Map
+)
+
+// structField 结构体按 encoding/json 规则展开后的字段。
+type structField struct {
+	name      string
+	index     []int // 字段在结构体中的路径，包含匿名结构体
+	tagged    bool  // 名称是否来自 json tag
+	omitEmpty bool
+	quoted    bool // ,string 选项
+}
+
+// TranslateLocalized 根据 context 中的语言获取多语言文本，语言规则与 NewHTTPError 一致。
+func TranslateLocalized(ctx context.Context, s LocalizedString) string {
+	return s.Get(GetLanguageByCtx(ctx), DefaultLanguage)
+}
+
+// TranslateByCtx 根据 context 中的语言、租户和时区获取国际化内容，模板参数中的时间会转换到请求时区。
+func TranslateByCtx(ctx context.Context, messageId string, templateData map[string]interface{}) string {
+	return TranslateTenant(GetTenantByCtx(ctx), GetLanguageByCtx(ctx), messageId, InLocation(templateData, GetLocationByCtx(ctx)))
+}
+
+// collapseLocalized 将 body 中所有 LocalizedString 替换为指定语言的文本，其余字段保持 JSON 序列化结果不变。
+func collapseLocalized(body interface{}, lang string) interface{} {
+	return collapseValue(reflect.ValueOf(body), lang)
+}
+
+func collapseValue(v reflect.Value, lang string) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+
+	t := v.Type()
+	if t == localizedStringType {
+		return v.Interface().(LocalizedString).Get(lang, DefaultLanguage)
+	}
+	if !containsLocalized(t) {
+		return v.Interface()
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return collapseValue(v.Elem(), lang)
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		items := make([]interface{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			items[i] = collapseValue(v.Index(i), lang)
+		}
+		return items
+
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		m := reflect.MakeMapWithSize(reflect.MapOf(t.Key(), reflect.TypeOf((*interface{})(nil)).Elem()), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			item := collapseValue(iter.Value(), lang)
+			if item == nil {
+				m.SetMapIndex(iter.Key(), reflect.Zero(m.Type().Elem()))
+				continue
+			}
+			m.SetMapIndex(iter.Key(), reflect.ValueOf(item))
+		}
+		return m.Interface()
+
+	case reflect.Struct:
+		fields := make(map[string]interface{})
+		collapseStruct(v, lang, fields)
+		return fields
+	}
+
+	return v.Interface()
+}
+
+// collapseStruct 按 encoding/json 的字段规则展开结构体，包括匿名结构体字段的平铺、同名字段的取舍和 ,string 选项。
+func collapseStruct(v reflect.Value, lang string, fields map[string]interface{}) {
+	for _, f := range structFields(v.Type()) {
+		fv, ok := fieldByIndex(v, f.index)
+		if !ok {
+			// 所在的匿名结构体指针为 nil
+			continue
+		}
+		if f.omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+		if f.quoted {
+			fields[f.name] = quotedValue(fv)
+			continue
+		}
+		fields[f.name] = collapseValue(fv, lang)
+	}
+}
+
+// fieldByIndex 按路径获取字段，路径上的指针为 nil 时返回 false。
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// quotedValue 返回 ,string 选项的输出，即字段 JSON 序列化结果对应的字符串。
+func quotedValue(v reflect.Value) interface{} {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	b, err := json.Marshal(v.Interface())
+	if err != nil {
+		return v.Interface()
+	}
+	return string(b)
+}
+
+// structFields 返回结构体类型按 encoding/json 规则展开后的字段：
+// 同名字段中层级最浅的生效；同一层级有多个时，只有一个带 json tag 的字段生效，否则都被忽略。
+func structFields(t reflect.Type) []structField {
+	if cached, ok := structFieldsCache.Load(t); ok {
+		return cached.([]structField)
+	}
+
+	type embeddedStruct struct {
+		typ   reflect.Type
+		index []int
+	}
+
+	var candidates []structField
+	visited := map[reflect.Type]bool{}
+	next := []embeddedStruct{{typ: t}}
+	for len(next) > 0 {
+		current := next
+		next = nil
+		for _, es := range current {
+			// 同一类型在同一层级多次出现时都展开，使其字段同名冲突而被忽略
+			if visited[es.typ] {
+				continue
+			}
+
+			for i := 0; i < es.typ.NumField(); i++ {
+				sf := es.typ.Field(i)
+				if sf.Anonymous {
+					ft := sf.Type
+					if ft.Kind() == reflect.Ptr {
+						ft = ft.Elem()
+					}
+					if !sf.IsExported() && ft.Kind() != reflect.Struct {
+						continue
+					}
+				} else if !sf.IsExported() {
+					continue
+				}
+
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, opts, _ := strings.Cut(tag, ",")
+
+				index := make([]int, len(es.index)+1)
+				copy(index, es.index)
+				index[len(es.index)] = i
+
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+
+				if name == "" && sf.Anonymous && ft.Kind() == reflect.Struct {
+					next = append(next, embeddedStruct{typ: ft, index: index})
+					continue
+				}
+
+				quoted := false
+				if hasOption(opts, "string") {
+					switch ft.Kind() {
+					case reflect.Bool,
+						reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+						reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+						reflect.Float32, reflect.Float64,
+						reflect.String:
+						quoted = true
+					}
+				}
+
+				f := structField{
+					name:      name,
+					index:     index,
+					tagged:    name != "",
+					omitEmpty: hasOption(opts, "omitempty"),
+					quoted:    quoted,
+				}
+				if f.name == "" {
+					f.name = sf.Name
+				}
+				candidates = append(candidates, f)
+			}
+		}
+		for _, es := range current {
+			visited[es.typ] = true
+		}
+	}
+
+	// 按名称分组，组内按层级、是否带 tag 排序
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.name != b.name {
+			return a.name < b.name
+		}
+		if len(a.index) != len(b.index) {
+			return len(a.index) < len(b.index)
+		}
+		return a.tagged && !b.tagged
+	})
+
+	fields := make([]structField, 0, len(candidates))
+	for i := 0; i < len(candidates); {
+		j := i + 1
+		for j < len(candidates) && candidates[j].name == candidates[i].name {
+			j++
+		}
+		if f, ok := dominantField(candidates[i:j]); ok {
+			fields = append(fields, f)
+		}
+		i = j
+	}
+
+	structFieldsCache.Store(t, fields)
+	return fields
+}
+
+// dominantField 从已排序的同名字段中选出生效的字段。
+func dominantField(fields []structField) (structField, bool) {
+	if len(fields) > 1 && len(fields[0].index) == len(fields[1].index) && fields[0].tagged == fields[1].tagged {
+		return structField{}, false
+	}
+	return fields[0], true
+}
+
+func hasOption(opts string, option string) bool {
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+func containsLocalized(t reflect.Type) bool {
+	if cached, ok := localizedTypeCache.Load(t); ok {
+		return cached.(bool)
+	}
+	result := typeContainsLocalized(t, make(map[reflect.Type]bool))
+	localizedTypeCache.Store(t, result)
+	return result
+}
+
+func typeContainsLocalized(t reflect.Type, visiting map[reflect.Type]bool) bool {
+	if t == localizedStringType {
+		return true
+	}
+	if visiting[t] {
+		return false
+	}
+	visiting[t] = true
+
+	// 自定义序列化的类型保持原样
+	if t.Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(jsonMarshalerType) {
+		return false
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return typeContainsLocalized(t.Elem(), visiting)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if (sf.IsExported() || sf.Anonymous) && typeContainsLocalized(sf.Type, visiting) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package rest
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	. "github.com/RockyRori/AdoLib/i18n"
+)
+
+type collapseInner struct {
+	Name  LocalizedString `json:"name"`
+	Dup   string
+	Shown string `json:"shown"`
+}
+
+type collapseOther struct {
+	Dup   string
+	Shown string
+	Deep  string `json:"deep"`
+}
+
+type collapseDeep struct {
+	collapseOther
+}
+
+type collapseBody struct {
+	ID    int64  `json:"id,string"`
+	Count *int   `json:"count,string,omitempty"`
+	Label string `json:",string"`
+	collapseInner
+	collapseDeep
+	Skip string `json:"-"`
+}
+
+func TestCollapseLocalizedMatchesJSON(t *testing.T) {
+	count := 3
+	body := collapseBody{
+		ID:    42,
+		Count: &count,
+		Label: "a",
+		collapseInner: collapseInner{
+			Name:  LocalizedString{"zh-CN": "名称", "en-US": "name"},
+			Dup:   "inner",
+			Shown: "tagged",
+		},
+		collapseDeep: collapseDeep{collapseOther{Dup: "deep", Shown: "untagged", Deep: "deep"}},
+		Skip:         "skip",
+	}
+
+	got, err := json.Marshal(collapseLocalized(body, "en-US"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 除 LocalizedString 外应与 encoding/json 的结果一致
+	body.collapseInner.Name = nil
+	want, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gotFields, wantFields map[string]interface{}
+	if err = json.Unmarshal(got, &gotFields); err != nil {
+		t.Fatal(err)
+	}
+	if err = json.Unmarshal(want, &wantFields); err != nil {
+		t.Fatal(err)
+	}
+	wantFields["name"] = "name"
+
+	if !reflect.DeepEqual(gotFields, wantFields) {
+		t.Errorf("collapseLocalized = %s, want %v", got, wantFields)
+	}
+}
+
+func TestCollapseLocalizedDuplicateEmbedded(t *testing.T) {
+	type a struct{ collapseInner }
+	type b struct{ collapseInner }
+	type body struct {
+		a
+		b
+		Title LocalizedString `json:"title"`
+	}
+
+	got := collapseLocalized(body{Title: LocalizedString{"en-US": "t"}}, "en-US")
+	want := map[string]interface{}{"title": "t"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("collapseLocalized = %v, want %v", got, want)
+	}
+}
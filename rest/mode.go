@@ -0,0 +1,22 @@
+package rest
+
+// Mode 运行环境。
+type Mode string
+
+const (
+	ModeDevelopment Mode = "development"
+	ModeProduction  Mode = "production"
+)
+
+// RunMode 当前运行环境，生产环境下错误响应不包含内部细节
+var RunMode = ModeDevelopment
+
+// SetMode 设置运行环境。
+func SetMode(mode Mode) {
+	RunMode = mode
+}
+
+// IsProduction 是否为生产环境。
+func IsProduction() bool {
+	return RunMode == ModeProduction
+}
@@ -0,0 +1,153 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ItemError 批量操作中单个条目的错误。
+type ItemError struct {
+	Index *int   // 条目下标
+	ID    string // 条目ID
+	Err   error  // 条目错误，非 HTTPError 时按内部错误处理
+}
+
+// MultiError 批量操作的错误集合，ReplyError 会将其作为一个响应返回。
+type MultiError struct {
+	HTTPCode int
+	Items    []ItemError
+}
+
+// multiErrorItem 批量错误响应中的条目。
+type multiErrorItem struct {
+	Index  *int   `json:"index,omitempty"`
+	ID     string `json:"id,omitempty"`
+	Status int    `json:"status"`
+	BaseError
+}
+
+// multiErrorBody 批量错误响应体。
+type multiErrorBody struct {
+	Errors    []multiErrorItem `json:"errors"`
+	RequestID string           `json:"request_id,omitempty"`
+	TraceID   string           `json:"trace_id,omitempty"`
+}
+
+// NewMultiError 创建批量错误，httpCode 为 0 时使用 207 Multi-Status。
+func NewMultiError(httpCode int) *MultiError {
+	if httpCode == 0 {
+		httpCode = http.StatusMultiStatus
+	}
+	return &MultiError{
+		HTTPCode: httpCode,
+	}
+}
+
+// Add 按下标添加条目错误，err 为 nil 时忽略。
+func (m *MultiError) Add(index int, err error) *MultiError {
+	if err != nil {
+		m.Items = append(m.Items, ItemError{Index: &index, Err: err})
+	}
+	return m
+}
+
+// AddByID 按ID添加条目错误，err 为 nil 时忽略。
+func (m *MultiError) AddByID(id string, err error) *MultiError {
+	if err != nil {
+		m.Items = append(m.Items, ItemError{ID: id, Err: err})
+	}
+	return m
+}
+
+// Len 返回条目错误数量。
+func (m *MultiError) Len() int {
+	return len(m.Items)
+}
+
+// ErrorOrNil 没有条目错误时返回 nil。
+func (m *MultiError) ErrorOrNil() error {
+	if m == nil || len(m.Items) == 0 {
+		return nil
+	}
+	return m
+}
+
+// Unwrap 返回所有条目错误，支持 errors.Is/As。
+func (m *MultiError) Unwrap() []error {
+	errs := make([]error, 0, len(m.Items))
+	for _, item := range m.Items {
+		errs = append(errs, item.Err)
+	}
+	return errs
+}
+
+func (m *MultiError) Error() string {
+	msgs := make([]string, 0, len(m.Items))
+	for _, item := range m.Items {
+		if item.Index != nil {
+			msgs = append(msgs, fmt.Sprintf("item %d: %v", *item.Index, item.Err))
+		} else {
+			msgs = append(msgs, fmt.Sprintf("item %s: %v", item.ID, item.Err))
+		}
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// body 生成响应体，非 HTTPError 的条目按 ctx 中的语言生成 InternalError。
+func (m *MultiError) body(ctx context.Context) (multiErrorBody, []*HTTPError) {
+	items := make([]multiErrorItem, 0, len(m.Items))
+	httpErrs := make([]*HTTPError, 0, len(m.Items))
+	for _, item := range m.Items {
+		var httpErr *HTTPError
+		if !errors.As(item.Err, &httpErr) {
+			httpErr = NewHTTPError(ctx, http.StatusInternalServerError, InternalError).WithErrorDetails(internalErrorDetails(item.Err))
+		}
+		if httpErr.IsLazy() {
+			httpErr = httpErr.Localize(ctx)
+		}
+		httpErrs = append(httpErrs, httpErr)
+		items = append(items, multiErrorItem{
+			Index:     item.Index,
+			ID:        item.ID,
+			Status:    httpErr.HTTPCode,
+			BaseError: httpErr.BaseError,
+		})
+	}
+	return multiErrorBody{
+		Errors: items,
+	}, httpErrs
+}
+
+// replyMultiError 响应批量错误。
+func replyMultiError(c *gin.Context, m *MultiError) {
+	body, httpErrs := m.body(GetLanguageCtx(c))
+
+	requestID := GetRequestID(c)
+	traceID := GetTraceID(c.Request.Context())
+	for _, httpErr := range httpErrs {
+		observeError(c, httpErr, requestID, traceID)
+	}
+	for i := range body.Errors {
+		body.Errors[i].ErrorDetails = redactDetails(body.Errors[i].ErrorDetails)
+	}
+	if ErrorTrace.InBody {
+		body.RequestID = requestID
+		body.TraceID = traceID
+	}
+	setTraceHeaders(c, requestID, traceID)
+
+	format, _ := negotiateBodyFormat(c.GetHeader("Accept"))
+	contentType, b, err := marshalBody(format, "response", body)
+	if err != nil {
+		contentType = ContentTypeJson
+		b, _ = json.Marshal(body)
+	}
+	c.Writer.Header().Set(ContentTypeKey, contentType)
+	c.String(m.HTTPCode, string(b))
+}
@@ -0,0 +1,106 @@
+package rest_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/RockyRori/AdoLib/rest"
+	"github.com/RockyRori/AdoLib/rest/resttest"
+	"github.com/gin-gonic/gin"
+)
+
+func TestReplyMultiError(t *testing.T) {
+	engine := resttest.NewEngine(resttest.Route{
+		Method: http.MethodPost,
+		Path:   "/users/batch",
+		Handlers: []gin.HandlerFunc{func(c *gin.Context) {
+			m := rest.NewMultiError(0).
+				Add(0, nil).
+				Add(1, rest.NewLazyHTTPError(http.StatusNotFound, rest.NotFound).
+					WithDescription(map[string]interface{}{"Resource": "user"})).
+				AddByID("u-3", errors.New("db down"))
+			rest.ReplyError(c, m.ErrorOrNil())
+		}},
+	})
+
+	resp := resttest.Do(t, engine, resttest.Request{Method: http.MethodPost, Path: "/users/batch", Language: "zh-CN"})
+	resp.AssertStatus(t, http.StatusMultiStatus)
+
+	var body struct {
+		Errors []struct {
+			Index       *int   `json:"index"`
+			ID          string `json:"id"`
+			Status      int    `json:"status"`
+			ErrorCode   string `json:"error_code"`
+			Description string `json:"description"`
+		} `json:"errors"`
+		RequestID string `json:"request_id"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Errors) != 2 {
+		t.Fatalf("got %d item errors, want 2, body: %s", len(body.Errors), resp.Body.String())
+	}
+
+	first := body.Errors[0]
+	if first.Index == nil || *first.Index != 1 || first.Status != http.StatusNotFound ||
+		first.ErrorCode != rest.NotFound || first.Description != "user不存在" {
+		t.Errorf("first item = %+v", first)
+	}
+	second := body.Errors[1]
+	if second.Index != nil || second.ID != "u-3" || second.Status != http.StatusInternalServerError ||
+		second.ErrorCode != rest.InternalError || second.Description != "内部错误" {
+		t.Errorf("second item = %+v", second)
+	}
+	if body.RequestID == "" {
+		t.Error("request_id should be set once for the whole response")
+	}
+}
+
+func TestMultiErrorOrNil(t *testing.T) {
+	m := rest.NewMultiError(http.StatusBadRequest).Add(0, nil)
+	if m.ErrorOrNil() != nil {
+		t.Error("ErrorOrNil should return nil without item errors")
+	}
+
+	m.Add(1, rest.Code(rest.NotFound))
+	err := m.ErrorOrNil()
+	if err == nil || m.Len() != 1 {
+		t.Fatalf("ErrorOrNil = %v, Len = %d", err, m.Len())
+	}
+	if !errors.Is(err, rest.Code(rest.NotFound)) {
+		t.Error("errors.Is should search the item errors")
+	}
+}
+
+func TestReplyErrorWrappedMultiError(t *testing.T) {
+	batchErr := rest.NewMultiError(0).Add(0, rest.Code(rest.NotFound))
+	engine := resttest.NewEngine(
+		resttest.Route{
+			Method: http.MethodPost,
+			Path:   "/http-error",
+			Handlers: []gin.HandlerFunc{func(c *gin.Context) {
+				rest.ReplyError(c, rest.NewHTTPError(rest.GetLanguageCtx(c), http.StatusBadRequest, rest.BadRequest).Wrap(batchErr))
+			}},
+		},
+		resttest.Route{
+			Method: http.MethodPost,
+			Path:   "/multi-error",
+			Handlers: []gin.HandlerFunc{func(c *gin.Context) {
+				rest.ReplyError(c, fmt.Errorf("import users: %w", batchErr))
+			}},
+		},
+	)
+
+	// 外层的 HTTPError 优先
+	resttest.Do(t, engine, resttest.Request{Method: http.MethodPost, Path: "/http-error"}).
+		AssertStatus(t, http.StatusBadRequest).
+		AssertErrorCode(t, rest.BadRequest)
+
+	resttest.Do(t, engine, resttest.Request{Method: http.MethodPost, Path: "/multi-error"}).
+		AssertStatus(t, http.StatusMultiStatus)
+}
@@ -0,0 +1,94 @@
+package rest
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// codeSegmentRegexp 错误码每一段的格式，大写字母开头的字母数字组合
+var codeSegmentRegexp = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+
+// NamingScheme 错误码命名规则，格式为 Service.Module.Reason。
+type NamingScheme struct {
+	ServicePrefix string // 服务前缀，例如 Order，为空时不校验服务
+}
+
+// ErrorCodeParts 错误码的组成部分。
+type ErrorCodeParts struct {
+	Service string
+	Module  string
+	Reason  string
+}
+
+func (p ErrorCodeParts) String() string {
+	return p.Service + "." + p.Module + "." + p.Reason
+}
+
+// ParseErrorCode 按 Service.Module.Reason 格式解析错误码。
+func ParseErrorCode(errorCode string) (ErrorCodeParts, error) {
+	segments := strings.Split(errorCode, ".")
+	if len(segments) != 3 {
+		return ErrorCodeParts{}, fmt.Errorf("errorCode %s does not match Service.Module.Reason", errorCode)
+	}
+	for _, segment := range segments {
+		if !codeSegmentRegexp.MatchString(segment) {
+			return ErrorCodeParts{}, fmt.Errorf("errorCode %s has invalid segment %q, segments must match %s", errorCode, segment, codeSegmentRegexp)
+		}
+	}
+
+	return ErrorCodeParts{
+		Service: segments[0],
+		Module:  segments[1],
+		Reason:  segments[2],
+	}, nil
+}
+
+// Validate 校验错误码是否符合命名规则。
+func (s NamingScheme) Validate(errorCode string) error {
+	parts, err := ParseErrorCode(errorCode)
+	if err != nil {
+		return err
+	}
+	if s.ServicePrefix != "" && parts.Service != s.ServicePrefix {
+		return fmt.Errorf("errorCode %s must start with service prefix %s", errorCode, s.ServicePrefix)
+	}
+	return nil
+}
+
+// Code 使用服务前缀生成错误码。
+func (s NamingScheme) Code(module string, reason string) string {
+	return ErrorCodeParts{Service: s.ServicePrefix, Module: module, Reason: reason}.String()
+}
+
+// SetNamingScheme 设置错误码命名规则，之后注册的错误码必须符合该规则，系统默认错误不受影响。
+func (r *ErrorRegistry) SetNamingScheme(scheme NamingScheme) error {
+	if scheme.ServicePrefix != "" && !codeSegmentRegexp.MatchString(scheme.ServicePrefix) {
+		return fmt.Errorf("invalid service prefix %q, must match %s", scheme.ServicePrefix, codeSegmentRegexp)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.namingScheme = &scheme
+	return nil
+}
+
+// NamingScheme 返回错误码命名规则，未设置时返回 false。
+func (r *ErrorRegistry) NamingScheme() (NamingScheme, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.namingScheme == nil {
+		return NamingScheme{}, false
+	}
+	return *r.namingScheme, true
+}
+
+// validateCode 按命名规则校验错误码，未设置命名规则时不校验，覆盖系统默认错误时也不校验。
+func (r *ErrorRegistry) validateCode(errorCode string) error {
+	scheme, ok := r.NamingScheme()
+	if !ok || r.isBuiltin(errorCode) {
+		return nil
+	}
+	return scheme.Validate(errorCode)
+}
@@ -0,0 +1,92 @@
+package rest_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/RockyRori/AdoLib/rest"
+	"github.com/RockyRori/AdoLib/rest/resttest"
+)
+
+func TestParseErrorCode(t *testing.T) {
+	tests := []struct {
+		errorCode string
+		want      rest.ErrorCodeParts
+		wantErr   bool
+	}{
+		{errorCode: "Order.Payment.Timeout", want: rest.ErrorCodeParts{Service: "Order", Module: "Payment", Reason: "Timeout"}},
+		{errorCode: "Order2.V1.NotFound3", want: rest.ErrorCodeParts{Service: "Order2", Module: "V1", Reason: "NotFound3"}},
+		{errorCode: "NotFound", wantErr: true},
+		{errorCode: "Order.Payment", wantErr: true},
+		{errorCode: "Order.Payment.Timeout.Extra", wantErr: true},
+		{errorCode: "order.Payment.Timeout", wantErr: true},
+		{errorCode: "Order..Timeout", wantErr: true},
+		{errorCode: "Order.Pay-ment.Timeout", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := rest.ParseErrorCode(tt.errorCode)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseErrorCode(%q) err = %v, wantErr %v", tt.errorCode, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseErrorCode(%q) = %+v, want %+v", tt.errorCode, got, tt.want)
+		}
+		if !tt.wantErr && got.String() != tt.errorCode {
+			t.Errorf("String() = %q, want %q", got.String(), tt.errorCode)
+		}
+	}
+}
+
+func TestNamingSchemeValidate(t *testing.T) {
+	tests := []struct {
+		scheme    rest.NamingScheme
+		errorCode string
+		wantErr   bool
+	}{
+		{scheme: rest.NamingScheme{}, errorCode: "Order.Payment.Timeout"},
+		{scheme: rest.NamingScheme{}, errorCode: "Invalid", wantErr: true},
+		{scheme: rest.NamingScheme{ServicePrefix: "Order"}, errorCode: "Order.Payment.Timeout"},
+		{scheme: rest.NamingScheme{ServicePrefix: "Order"}, errorCode: "User.Payment.Timeout", wantErr: true},
+		{scheme: rest.NamingScheme{ServicePrefix: "Order"}, errorCode: "OrderX.Payment.Timeout", wantErr: true},
+	}
+	for _, tt := range tests {
+		if err := tt.scheme.Validate(tt.errorCode); (err != nil) != tt.wantErr {
+			t.Errorf("%+v.Validate(%q) err = %v, wantErr %v", tt.scheme, tt.errorCode, err, tt.wantErr)
+		}
+	}
+}
+
+func TestNamingSchemeCode(t *testing.T) {
+	scheme := rest.NamingScheme{ServicePrefix: "Order"}
+	code := scheme.Code("Payment", "Timeout")
+	if code != "Order.Payment.Timeout" {
+		t.Errorf("Code = %q, want %q", code, "Order.Payment.Timeout")
+	}
+	if err := scheme.Validate(code); err != nil {
+		t.Errorf("generated code should be valid: %v", err)
+	}
+}
+
+func TestRegistryNamingScheme(t *testing.T) {
+	resttest.LoadLocale(t, "testdata/override")
+
+	registry := rest.NewErrorRegistry()
+	if err := registry.SetNamingScheme(rest.NamingScheme{ServicePrefix: "order"}); err == nil {
+		t.Error("SetNamingScheme should reject an invalid service prefix")
+	}
+	if _, ok := registry.NamingScheme(); ok {
+		t.Error("a rejected naming scheme should not be set")
+	}
+
+	if err := registry.SetNamingScheme(rest.NamingScheme{ServicePrefix: "Order"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.Register([]string{"DemoUserNotFound"}); err == nil || !strings.Contains(err.Error(), "Service.Module.Reason") {
+		t.Errorf("codes that do not match the naming scheme should be rejected, err = %v", err)
+	}
+	// 覆盖系统默认错误不受命名规则限制
+	if err := registry.Register([]string{rest.NotFound}); err != nil {
+		t.Errorf("overriding a built-in code failed: %v", err)
+	}
+}
@@ -0,0 +1,212 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ContentTypeXml  = "application/xml"
+	ContentTypeYaml = "application/yaml"
+)
+
+// bodyFormat 响应体格式。
+type bodyFormat int
+
+const (
+	formatJSON bodyFormat = iota
+	formatXML
+	formatYAML
+)
+
+var (
+	// mediaTypeFormats Accept 中支持的媒体类型
+	mediaTypeFormats = map[string]bodyFormat{
+		"*/*":                  formatJSON,
+		"application/*":        formatJSON,
+		ContentTypeJson:        formatJSON,
+		ContentTypeProblemJson: formatJSON,
+		ContentTypeXml:         formatXML,
+		"text/xml":             formatXML,
+		ContentTypeYaml:        formatYAML,
+		"application/x-yaml":   formatYAML,
+		"text/yaml":            formatYAML,
+		"text/x-yaml":          formatYAML,
+	}
+
+	formatContentTypes = map[bodyFormat]string{
+		formatJSON: ContentTypeJson,
+		formatXML:  ContentTypeXml,
+		formatYAML: ContentTypeYaml,
+	}
+
+	xmlNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+)
+
+// acceptRange Accept 中的一项。
+type acceptRange struct {
+	mediaType string
+	q         float64
+}
+
+// parseAccept 解析 Accept，按 q 值从高到低排序，q 值相同时保持原顺序，忽略 q=0 的项。
+func parseAccept(accept string) []acceptRange {
+	var ranges []acceptRange
+	for _, item := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(item))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		ranges = append(ranges, acceptRange{mediaType: mediaType, q: q})
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].q > ranges[j].q
+	})
+	return ranges
+}
+
+// negotiateBodyFormat 根据 Accept 选择响应体格式，没有可接受的格式时返回 false。
+func negotiateBodyFormat(accept string) (bodyFormat, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return formatJSON, true
+	}
+
+	for _, r := range parseAccept(accept) {
+		if format, ok := mediaTypeFormats[r.mediaType]; ok {
+			return format, true
+		}
+	}
+	return formatJSON, false
+}
+
+// marshalBody 按格式序列化响应体，XML 和 YAML 的字段名与 JSON 一致，root 为 XML 根元素名。
+func marshalBody(format bodyFormat, root string, body interface{}) (string, []byte, error) {
+	contentType := formatContentTypes[format]
+	if format == formatJSON {
+		b, err := json.Marshal(body)
+		return contentType, b, err
+	}
+
+	// 先转换为 JSON 的通用结构，保证字段名和 JSON 一致
+	generic, err := toGeneric(body)
+	if err != nil {
+		return contentType, nil, err
+	}
+
+	switch format {
+	case formatXML:
+		var buf bytes.Buffer
+		buf.WriteString(xml.Header)
+		enc := xml.NewEncoder(&buf)
+		if err = writeXMLValue(enc, root, nil, generic); err != nil {
+			return contentType, nil, err
+		}
+		if err = enc.Flush(); err != nil {
+			return contentType, nil, err
+		}
+		return contentType, buf.Bytes(), nil
+
+	case formatYAML:
+		b, err := yaml.Marshal(generic)
+		return contentType, b, err
+	}
+	return contentType, nil, fmt.Errorf("unsupported body format %d", format)
+}
+
+func toGeneric(body interface{}) (interface{}, error) {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var generic interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err = dec.Decode(&generic); err != nil {
+		return nil, err
+	}
+	return convertNumbers(generic), nil
+}
+
+// convertNumbers 将 json.Number 转换为 int64 或 float64，避免 YAML 将数字序列化为字符串。
+func convertNumbers(v interface{}) interface{} {
+	switch data := v.(type) {
+	case json.Number:
+		if i, err := data.Int64(); err == nil {
+			return i
+		}
+		if f, err := data.Float64(); err == nil {
+			return f
+		}
+		return data.String()
+	case map[string]interface{}:
+		for k, item := range data {
+			data[k] = convertNumbers(item)
+		}
+	case []interface{}:
+		for i, item := range data {
+			data[i] = convertNumbers(item)
+		}
+	}
+	return v
+}
+
+// writeXMLValue 将通用结构写为 XML，对象的key作为子元素名，不是合法元素名时写为 <item key="...">，数组元素写为 <item>。
+func writeXMLValue(enc *xml.Encoder, name string, attrs []xml.Attr, v interface{}) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}, Attr: attrs}
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	switch data := v.(type) {
+	case nil:
+	case map[string]interface{}:
+		keys := make([]string, 0, len(data))
+		for k := range data {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			childName, childAttrs := k, []xml.Attr(nil)
+			if !xmlNameRegexp.MatchString(k) || strings.HasPrefix(strings.ToLower(k), "xml") {
+				childName = "item"
+				childAttrs = []xml.Attr{{Name: xml.Name{Local: "key"}, Value: k}}
+			}
+			if err := writeXMLValue(enc, childName, childAttrs, data[k]); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, item := range data {
+			if err := writeXMLValue(enc, "item", nil, item); err != nil {
+				return err
+			}
+		}
+	default:
+		if err := enc.EncodeToken(xml.CharData(fmt.Sprint(data))); err != nil {
+			return err
+		}
+	}
+
+	return enc.EncodeToken(start.End())
+}
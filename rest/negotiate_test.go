@@ -0,0 +1,69 @@
+package rest_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/RockyRori/AdoLib/rest"
+	"github.com/RockyRori/AdoLib/rest/resttest"
+	"github.com/gin-gonic/gin"
+)
+
+func TestReplyOKNegotiation(t *testing.T) {
+	type user struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+	engine := resttest.NewEngine(resttest.Route{
+		Method: http.MethodGet,
+		Path:   "/users/1",
+		Handlers: []gin.HandlerFunc{func(c *gin.Context) {
+			rest.ReplyOK(c, http.StatusOK, user{Name: "rocky", Count: 2})
+		}},
+	})
+
+	tests := []struct {
+		accept      string
+		contentType string
+		body        string
+	}{
+		{accept: "", contentType: rest.ContentTypeJson, body: `{"name":"rocky","count":2}`},
+		{accept: "*/*", contentType: rest.ContentTypeJson, body: `{"name":"rocky","count":2}`},
+		{accept: "application/xml", contentType: rest.ContentTypeXml,
+			body: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" + `<response><count>2</count><name>rocky</name></response>`},
+		{accept: "application/json;q=0.5, application/yaml", contentType: rest.ContentTypeYaml, body: "count: 2\nname: rocky\n"},
+	}
+	for _, tt := range tests {
+		resp := resttest.Do(t, engine, resttest.Request{
+			Method:  http.MethodGet,
+			Path:    "/users/1",
+			Headers: map[string]string{"Accept": tt.accept},
+		})
+		resp.AssertStatus(t, http.StatusOK)
+		if got := resp.Header().Get(rest.ContentTypeKey); got != tt.contentType {
+			t.Errorf("Accept %q: Content-Type = %q, want %q", tt.accept, got, tt.contentType)
+		}
+		if got := resp.Body.String(); got != tt.body {
+			t.Errorf("Accept %q: body = %q, want %q", tt.accept, got, tt.body)
+		}
+	}
+}
+
+func TestReplyOKNotAcceptable(t *testing.T) {
+	engine := resttest.NewEngine(resttest.Route{
+		Method: http.MethodGet,
+		Path:   "/users/1",
+		Handlers: []gin.HandlerFunc{func(c *gin.Context) {
+			rest.ReplyOK(c, http.StatusOK, map[string]string{"name": "rocky"})
+		}},
+	})
+
+	resttest.Do(t, engine, resttest.Request{
+		Method:   http.MethodGet,
+		Path:     "/users/1",
+		Language: "en-US",
+		Headers:  map[string]string{"Accept": "text/csv"},
+	}).AssertStatus(t, http.StatusNotAcceptable).
+		AssertErrorCode(t, rest.NotAcceptable).
+		AssertErrorDetails(t, map[string]string{"accept": "text/csv"})
+}
@@ -0,0 +1,152 @@
+package rest
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// 日志级别顺序，用于与 ErrorLogLevel 比较
+var severityOrder = map[Severity]int{
+	SeverityDebug: 0,
+	SeverityInfo:  1,
+	SeverityWarn:  2,
+	SeverityError: 3,
+}
+
+var (
+	// ErrorLogLevel 记录错误响应的最低日志级别
+	ErrorLogLevel = SeverityInfo
+
+	// DefaultErrorLogFields 默认记录的日志字段
+	DefaultErrorLogFields = []string{"code", "status", "route", "lang", "request_id", "trace_id", "cause"}
+
+	errorCounter     metric.Int64Counter
+	errorCounterOnce sync.Once
+)
+
+// ErrorLogRecord 一次错误响应的日志记录。
+type ErrorLogRecord struct {
+	Severity   Severity
+	ErrorCode  string
+	HTTPStatus int
+	Route      string
+	Language   string
+	Tenant     string
+	RequestID  string
+	TraceID    string
+	Cause      error
+	Details    interface{} // 脱敏前的错误详情
+}
+
+// Format 按 fields 的顺序格式化为 key=value 形式，
+// 可选字段为 code、status、route、lang、tenant、request_id、trace_id、cause、details，未知字段被忽略。
+func (r ErrorLogRecord) Format(fields []string) string {
+	parts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		var value interface{}
+		switch field {
+		case "code":
+			value = r.ErrorCode
+		case "status":
+			value = r.HTTPStatus
+		case "route":
+			value = r.Route
+		case "lang":
+			value = r.Language
+		case "tenant":
+			value = r.Tenant
+		case "request_id":
+			value = r.RequestID
+		case "trace_id":
+			value = r.TraceID
+		case "cause":
+			value = r.Cause
+		case "details":
+			value = r.Details
+		default:
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s=%v", field, value))
+	}
+	return strings.Join(parts, " ")
+}
+
+// SeverityOf 获取错误的日志级别，错误码定义了级别时使用定义，否则 5xx 为 error，其余为 info。
+func SeverityOf(e *HTTPError) Severity {
+	registry := e.registry
+	if registry == nil {
+		registry = DefaultRegistry
+	}
+	if def, ok := registry.Definition(e.BaseError.ErrorCode); ok && def.Severity != "" {
+		return def.Severity
+	}
+
+	if e.HTTPCode >= http.StatusInternalServerError {
+		return SeverityError
+	}
+	return SeverityInfo
+}
+
+// getErrorCounter 获取错误响应计数器，使用全局 MeterProvider。
+func getErrorCounter() metric.Int64Counter {
+	errorCounterOnce.Do(func() {
+		var err error
+		errorCounter, err = otel.Meter("github.com/RockyRori/AdoLib/rest").Int64Counter(
+			"http.server.errors",
+			metric.WithDescription("Number of error responses replied by ReplyError"),
+		)
+		if err != nil {
+			log.Printf("create error counter failed: %v", err)
+		}
+	})
+	return errorCounter
+}
+
+// observeError 按日志级别记录错误响应，并按错误码、HTTP状态码和路由计数。
+func observeError(c *gin.Context, e *HTTPError, requestID string, traceID string) {
+	severity := SeverityOf(e)
+
+	if counter := getErrorCounter(); counter != nil {
+		counter.Add(c.Request.Context(), 1, metric.WithAttributes(
+			attribute.String("error_code", e.BaseError.ErrorCode),
+			attribute.Int("http_status", e.HTTPCode),
+			attribute.String("route", c.FullPath()),
+			attribute.String("severity", string(severity)),
+		))
+	}
+
+	if !ErrorTrace.Log || severityOrder[severity] < severityOrder[ErrorLogLevel] {
+		return
+	}
+
+	record := ErrorLogRecord{
+		Severity:   severity,
+		ErrorCode:  e.BaseError.ErrorCode,
+		HTTPStatus: e.HTTPCode,
+		Route:      c.FullPath(),
+		Language:   e.Language,
+		Tenant:     e.Tenant,
+		RequestID:  requestID,
+		TraceID:    traceID,
+		Cause:      e.Unwrap(),
+		Details:    e.BaseError.ErrorDetails,
+	}
+	if ErrorTrace.LogHook != nil {
+		ErrorTrace.LogHook(record)
+		return
+	}
+
+	fields := ErrorTrace.LogFields
+	if len(fields) == 0 {
+		fields = DefaultErrorLogFields
+	}
+	log.Printf("[%s] reply error: %s", severity, record.Format(fields))
+}
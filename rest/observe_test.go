@@ -0,0 +1,199 @@
+package rest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/RockyRori/AdoLib/rest"
+	"github.com/RockyRori/AdoLib/rest/resttest"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+var (
+	metricReader     *sdkmetric.ManualReader
+	metricReaderOnce sync.Once
+)
+
+// errorCounterReader 安装读取错误计数器的全局 MeterProvider。
+// 计数器只创建一次，只有首次设置的 MeterProvider 会接管全局委托，因此整个测试进程共用一个 reader。
+func errorCounterReader() *sdkmetric.ManualReader {
+	metricReaderOnce.Do(func() {
+		metricReader = sdkmetric.NewManualReader()
+		otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(metricReader)))
+	})
+	return metricReader
+}
+
+// errorCounts 按属性集汇总 http.server.errors 的累计值。
+func errorCounts(t *testing.T, reader *sdkmetric.ManualReader) map[attribute.Distinct]int64 {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	counts := make(map[attribute.Distinct]int64)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "http.server.errors" {
+				continue
+			}
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			if !ok {
+				t.Fatalf("http.server.errors data = %T, want metricdata.Sum[int64]", m.Data)
+			}
+			for _, dp := range sum.DataPoints {
+				counts[dp.Attributes.Equivalent()] += dp.Value
+			}
+		}
+	}
+	return counts
+}
+
+func TestErrorLogHook(t *testing.T) {
+	old := rest.ErrorTrace
+	t.Cleanup(func() { rest.ErrorTrace = old })
+
+	var records []rest.ErrorLogRecord
+	rest.ErrorTrace.LogHook = func(record rest.ErrorLogRecord) {
+		records = append(records, record)
+	}
+
+	cause := errors.New("db down")
+	engine := resttest.NewEngine(resttest.Route{
+		Method: http.MethodGet,
+		Path:   "/users/:id",
+		Handlers: []gin.HandlerFunc{func(c *gin.Context) {
+			rest.ReplyError(c, cause)
+		}},
+	})
+	resttest.Do(t, engine, resttest.Request{
+		Method:  http.MethodGet,
+		Path:    "/users/1",
+		Headers: map[string]string{"X-Request-ID": "req-1"},
+	}).AssertStatus(t, http.StatusInternalServerError)
+
+	if len(records) != 1 {
+		t.Fatalf("got %d log records, want 1", len(records))
+	}
+	record := records[0]
+	if record.ErrorCode != rest.InternalError || record.HTTPStatus != http.StatusInternalServerError ||
+		record.Route != "/users/:id" || record.RequestID != "req-1" || !errors.Is(record.Cause, cause) {
+		t.Errorf("record = %+v", record)
+	}
+}
+
+func TestErrorLogRecordFormat(t *testing.T) {
+	record := rest.ErrorLogRecord{ErrorCode: rest.NotFound, HTTPStatus: http.StatusNotFound, Tenant: "acme"}
+	got := record.Format([]string{"status", "code", "unknown", "tenant"})
+	if want := "status=404 code=NotFound tenant=acme"; got != want {
+		t.Errorf("Format = %q, want %q", got, want)
+	}
+}
+
+func TestErrorCounter(t *testing.T) {
+	reader := errorCounterReader()
+	resttest.LoadLocale(t, "testdata/locale")
+	registry := rest.NewErrorRegistry()
+	if err := registerDefinitionFile(t, registry, "testdata/definitions/errors.toml"); err != nil {
+		t.Fatal(err)
+	}
+
+	engine := resttest.NewEngine(
+		resttest.Route{
+			Method: http.MethodGet,
+			Path:   "/observe/users/:id",
+			Handlers: []gin.HandlerFunc{func(c *gin.Context) {
+				rest.ReplyError(c, rest.NewHTTPError(c, http.StatusNotFound, rest.NotFound))
+			}},
+		},
+		resttest.Route{
+			Method: http.MethodGet,
+			Path:   "/observe/quota",
+			Handlers: []gin.HandlerFunc{func(c *gin.Context) {
+				rest.ReplyError(c, registry.NewHTTPErrorByCode(c, "DemoQuotaExceeded"))
+			}},
+		},
+		resttest.Route{
+			Method: http.MethodGet,
+			Path:   "/observe/db",
+			Handlers: []gin.HandlerFunc{func(c *gin.Context) {
+				rest.ReplyError(c, errors.New("db down"))
+			}},
+		},
+	)
+
+	key := func(code string, status int, route string, severity rest.Severity) attribute.Distinct {
+		set := attribute.NewSet(
+			attribute.String("error_code", code),
+			attribute.Int("http_status", status),
+			attribute.String("route", route),
+			attribute.String("severity", string(severity)),
+		)
+		return set.Equivalent()
+	}
+	tests := []struct {
+		path  string
+		times int
+		key   attribute.Distinct
+	}{
+		{path: "/observe/users/1", times: 2, key: key(rest.NotFound, http.StatusNotFound, "/observe/users/:id", rest.SeverityInfo)},
+		// 定义中的级别优先于 5xx 默认的 error
+		{path: "/observe/quota", times: 1, key: key("DemoQuotaExceeded", rest.DefaultHTTPStatus, "/observe/quota", rest.SeverityWarn)},
+		{path: "/observe/db", times: 1, key: key(rest.InternalError, http.StatusInternalServerError, "/observe/db", rest.SeverityError)},
+	}
+
+	// 计数器是累计值，按差值断言以兼容 -count 重复运行
+	before := errorCounts(t, reader)
+	for _, tt := range tests {
+		for i := 0; i < tt.times; i++ {
+			resttest.Do(t, engine, resttest.Request{Method: http.MethodGet, Path: tt.path, Language: "en-US"})
+		}
+	}
+	after := errorCounts(t, reader)
+
+	for _, tt := range tests {
+		if got := after[tt.key] - before[tt.key]; got != int64(tt.times) {
+			t.Errorf("%s: counter delta = %d, want %d", tt.path, got, tt.times)
+		}
+	}
+}
+
+func TestSeverityOf(t *testing.T) {
+	resttest.LoadLocale(t, "testdata/locale")
+	registry := rest.NewErrorRegistry()
+	if err := registerDefinitionFile(t, registry, "testdata/definitions/errors.toml"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.WithValue(context.Background(), rest.XLangKey, "en-US")
+	tests := []struct {
+		name string
+		err  *rest.HTTPError
+		want rest.Severity
+	}{
+		{name: "builtin not found", err: rest.NewHTTPError(ctx, http.StatusNotFound, rest.NotFound), want: rest.SeverityInfo},
+		{name: "builtin too many requests", err: rest.NewHTTPError(ctx, http.StatusTooManyRequests, rest.TooManyRequests), want: rest.SeverityWarn},
+		{name: "builtin internal error", err: rest.NewHTTPError(ctx, http.StatusInternalServerError, rest.InternalError), want: rest.SeverityError},
+		// 定义中的级别优先于状态码
+		{name: "definition info on 5xx", err: registry.NewHTTPError(ctx, http.StatusInternalServerError, "DemoUserNotFound"), want: rest.SeverityInfo},
+		{name: "definition warn", err: registry.NewHTTPErrorByCode(ctx, "DemoQuotaExceeded"), want: rest.SeverityWarn},
+		// 没有定义时 5xx 为 error，其余为 info
+		{name: "undefined 5xx", err: &rest.HTTPError{HTTPCode: http.StatusBadGateway, BaseError: rest.BaseError{ErrorCode: "DemoUndefined"}}, want: rest.SeverityError},
+		{name: "undefined 4xx", err: &rest.HTTPError{HTTPCode: http.StatusConflict, BaseError: rest.BaseError{ErrorCode: "DemoUndefined"}}, want: rest.SeverityInfo},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rest.SeverityOf(tt.err); got != tt.want {
+				t.Errorf("SeverityOf = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorFormat 错误响应的格式。
+type ErrorFormat int
+
+const (
+	// ErrorFormatBase BaseError 格式，application/json
+	ErrorFormatBase ErrorFormat = iota
+	// ErrorFormatProblem RFC 7807 格式，application/problem+json
+	ErrorFormatProblem
+)
+
+const ContentTypeProblemJson = "application/problem+json"
+
+var (
+	// DefaultErrorFormat 错误响应的默认格式
+	DefaultErrorFormat = ErrorFormatBase
+	// NegotiateErrorFormat 为 true 时根据请求的 Accept 选择错误响应格式
+	NegotiateErrorFormat = false
+)
+
+// SetErrorFormat 设置错误响应的默认格式，negotiate 为 true 时允许通过 Accept 协商。
+func SetErrorFormat(format ErrorFormat, negotiate bool) {
+	DefaultErrorFormat = format
+	NegotiateErrorFormat = negotiate
+}
+
+// ProblemDetails RFC 7807 错误响应。
+type ProblemDetails struct {
+	Type       string                 `json:"type"`
+	Title      string                 `json:"title"`
+	Status     int                    `json:"status"`
+	Detail     string                 `json:"detail,omitempty"`
+	Instance   string                 `json:"instance,omitempty"`
+	Extensions map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON 将扩展字段与标准字段平铺输出。
+func (p ProblemDetails) MarshalJSON() ([]byte, error) {
+	fields := make(map[string]interface{}, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		fields[k] = v
+	}
+
+	fields["type"] = p.Type
+	fields["title"] = p.Title
+	fields["status"] = p.Status
+	if p.Detail != "" {
+		fields["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		fields["instance"] = p.Instance
+	}
+	return json.Marshal(fields)
+}
+
+// Problem 将 HTTPError 转换为 RFC 7807 格式。
+// ErrorLink 为绝对URL时作为 type，否则 type 为 about:blank；Description 作为 title；
+// ErrorDetails 为字符串时作为 detail；error_code、solution、error_link、error_details 以及
+// request_id、trace_id 作为扩展字段。
+func (e *HTTPError) Problem(instance string) ProblemDetails {
+	problemType := "about:blank"
+	if u, err := url.Parse(e.BaseError.ErrorLink); err == nil && u.IsAbs() {
+		problemType = e.BaseError.ErrorLink
+	}
+
+	var detail string
+	if s, ok := e.BaseError.ErrorDetails.(string); ok {
+		detail = s
+	}
+
+	extensions := map[string]interface{}{
+		"error_code":    e.BaseError.ErrorCode,
+		"solution":      e.BaseError.Solution,
+		"error_link":    e.BaseError.ErrorLink,
+		"error_details": e.BaseError.ErrorDetails,
+	}
+	if e.BaseError.RequestID != "" {
+		extensions["request_id"] = e.BaseError.RequestID
+	}
+	if e.BaseError.TraceID != "" {
+		extensions["trace_id"] = e.BaseError.TraceID
+	}
+
+	return ProblemDetails{
+		Type:       problemType,
+		Title:      e.BaseError.Description,
+		Status:     e.HTTPCode,
+		Detail:     detail,
+		Instance:   instance,
+		Extensions: extensions,
+	}
+}
+
+// errorFormat 获取本次请求的错误响应格式。
+func errorFormat(c *gin.Context) ErrorFormat {
+	if !NegotiateErrorFormat {
+		return DefaultErrorFormat
+	}
+
+	for _, r := range parseAccept(c.GetHeader("Accept")) {
+		switch r.mediaType {
+		case ContentTypeProblemJson:
+			return ErrorFormatProblem
+		case ContentTypeJson:
+			return ErrorFormatBase
+		}
+	}
+	return DefaultErrorFormat
+}
+
+// renderError 按格式序列化 HTTPError，返回 Content-Type 和响应体。
+// 非 problem+json 格式时按 Accept 选择 JSON、XML 或 YAML，没有可接受的格式时使用 JSON。
+func renderError(c *gin.Context, e *HTTPError) (string, string) {
+	if errorFormat(c) == ErrorFormatProblem {
+		b, _ := json.Marshal(e.Problem(c.Request.URL.RequestURI()))
+		return ContentTypeProblemJson, string(b)
+	}
+
+	format, _ := negotiateBodyFormat(c.GetHeader("Accept"))
+	if format == formatJSON {
+		return ContentTypeJson, e.Error()
+	}
+	contentType, b, err := marshalBody(format, "error", e.BaseError)
+	if err != nil {
+		return ContentTypeJson, e.Error()
+	}
+	return contentType, string(b)
+}
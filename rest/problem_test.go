@@ -0,0 +1,85 @@
+package rest_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/RockyRori/AdoLib/rest"
+	"github.com/RockyRori/AdoLib/rest/resttest"
+	"github.com/gin-gonic/gin"
+)
+
+func setErrorFormat(t *testing.T, format rest.ErrorFormat, negotiate bool) {
+	t.Helper()
+
+	oldFormat, oldNegotiate := rest.DefaultErrorFormat, rest.NegotiateErrorFormat
+	t.Cleanup(func() { rest.SetErrorFormat(oldFormat, oldNegotiate) })
+	rest.SetErrorFormat(format, negotiate)
+}
+
+func notFoundEngine() *gin.Engine {
+	return resttest.NewEngine(resttest.Route{
+		Method: http.MethodGet,
+		Path:   "/users/:id",
+		Handlers: []gin.HandlerFunc{func(c *gin.Context) {
+			err := rest.NewHTTPError(rest.GetLanguageCtx(c), http.StatusNotFound, rest.NotFound).
+				WithDescription(map[string]interface{}{"Resource": "user"}).
+				WithErrorDetails("no such user")
+			rest.ReplyError(c, err)
+		}},
+	})
+}
+
+func TestProblemFormat(t *testing.T) {
+	setErrorFormat(t, rest.ErrorFormatProblem, false)
+
+	resp := resttest.Do(t, notFoundEngine(), resttest.Request{Method: http.MethodGet, Path: "/users/1", Language: "en-US"})
+	resp.AssertStatus(t, http.StatusNotFound)
+	if got := resp.Header().Get(rest.ContentTypeKey); got != rest.ContentTypeProblemJson {
+		t.Errorf("Content-Type = %q, want %q", got, rest.ContentTypeProblemJson)
+	}
+
+	var problem map[string]interface{}
+	if err := json.Unmarshal(resp.Body.Bytes(), &problem); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"type":          "about:blank",
+		"title":         "user not found",
+		"status":        float64(http.StatusNotFound),
+		"detail":        "no such user",
+		"instance":      "/users/1",
+		"error_code":    rest.NotFound,
+		"error_details": "no such user",
+	}
+	for k, v := range want {
+		if problem[k] != v {
+			t.Errorf("%s = %v, want %v", k, problem[k], v)
+		}
+	}
+}
+
+func TestProblemNegotiation(t *testing.T) {
+	setErrorFormat(t, rest.ErrorFormatBase, true)
+
+	tests := []struct {
+		accept      string
+		contentType string
+	}{
+		{accept: "", contentType: rest.ContentTypeJson},
+		{accept: "application/problem+json", contentType: rest.ContentTypeProblemJson},
+		{accept: "application/json;q=0.5, application/problem+json", contentType: rest.ContentTypeProblemJson},
+		{accept: "application/problem+json;q=0.5, application/json", contentType: rest.ContentTypeJson},
+	}
+	for _, tt := range tests {
+		resp := resttest.Do(t, notFoundEngine(), resttest.Request{
+			Method:  http.MethodGet,
+			Path:    "/users/1",
+			Headers: map[string]string{"Accept": tt.accept},
+		})
+		if got := resp.Header().Get(rest.ContentTypeKey); got != tt.contentType {
+			t.Errorf("Accept %q: Content-Type = %q, want %q", tt.accept, got, tt.contentType)
+		}
+	}
+}
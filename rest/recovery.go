@@ -0,0 +1,46 @@
+package rest
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Recovery 捕获 panic 的 gin 中间件，记录堆栈后按请求语言响应 InternalError。
+// 非生产环境下错误详情包含 panic 信息。
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+			if r == http.ErrAbortHandler {
+				// 交给 net/http 处理中断的连接
+				panic(r)
+			}
+
+			log.Printf("panic recovered: request_id=%s trace_id=%s method=%s path=%s panic=%v\n%s",
+				GetRequestID(c), GetTraceID(c.Request.Context()), c.Request.Method, c.Request.URL.Path, r, debug.Stack())
+
+			if c.Writer.Written() {
+				// 响应已开始写入，无法再返回错误
+				c.Abort()
+				return
+			}
+
+			httpErr := NewHTTPError(GetLanguageCtx(c), http.StatusInternalServerError, InternalError).
+				Wrap(fmt.Errorf("panic: %v", r))
+			if !IsProduction() {
+				httpErr.WithErrorDetails(fmt.Sprint(r))
+			}
+			ReplyError(c, httpErr)
+			c.Abort()
+		}()
+
+		c.Next()
+	}
+}
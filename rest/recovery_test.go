@@ -0,0 +1,89 @@
+package rest_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/RockyRori/AdoLib/rest"
+	"github.com/RockyRori/AdoLib/rest/resttest"
+	"github.com/gin-gonic/gin"
+)
+
+func setMode(t *testing.T, mode rest.Mode) {
+	t.Helper()
+
+	old := rest.RunMode
+	t.Cleanup(func() { rest.SetMode(old) })
+	rest.SetMode(mode)
+}
+
+func recoveryEngine(handler gin.HandlerFunc) *gin.Engine {
+	return resttest.NewEngine(resttest.Route{
+		Method:   http.MethodGet,
+		Path:     "/panic",
+		Handlers: []gin.HandlerFunc{rest.Recovery(), handler},
+	})
+}
+
+func TestRecovery(t *testing.T) {
+	engine := recoveryEngine(func(c *gin.Context) {
+		panic("nil map write")
+	})
+
+	tests := []struct {
+		mode    rest.Mode
+		lang    string
+		details interface{}
+	}{
+		{mode: rest.ModeDevelopment, lang: "en-US", details: "nil map write"},
+		{mode: rest.ModeProduction, lang: "zh-CN", details: nil},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			setMode(t, tt.mode)
+
+			description := map[string]string{"zh-CN": "内部错误", "en-US": "Internal Server Error"}[tt.lang]
+			resttest.Do(t, engine, resttest.Request{Method: http.MethodGet, Path: "/panic", Language: tt.lang}).
+				AssertStatus(t, http.StatusInternalServerError).
+				AssertErrorCode(t, rest.InternalError).
+				AssertDescription(t, description).
+				AssertErrorDetails(t, tt.details)
+		})
+	}
+}
+
+func TestRecoveryAbortHandler(t *testing.T) {
+	var recovered interface{}
+	engine := resttest.NewEngine(resttest.Route{
+		Method: http.MethodGet,
+		Path:   "/abort",
+		Handlers: []gin.HandlerFunc{
+			func(c *gin.Context) {
+				defer func() { recovered = recover() }()
+				c.Next()
+			},
+			rest.Recovery(),
+			func(c *gin.Context) {
+				panic(http.ErrAbortHandler)
+			},
+		},
+	})
+
+	resttest.Do(t, engine, resttest.Request{Method: http.MethodGet, Path: "/abort"})
+	if recovered != http.ErrAbortHandler {
+		t.Errorf("recovered = %v, want http.ErrAbortHandler to be re-panicked", recovered)
+	}
+}
+
+func TestRecoveryAfterWrite(t *testing.T) {
+	engine := recoveryEngine(func(c *gin.Context) {
+		c.String(http.StatusOK, "partial")
+		panic("after write")
+	})
+
+	resp := resttest.Do(t, engine, resttest.Request{Method: http.MethodGet, Path: "/panic"})
+	resp.AssertStatus(t, http.StatusOK)
+	if got := resp.Body.String(); got != "partial" {
+		t.Errorf("body = %q, want the already written %q", got, "partial")
+	}
+}
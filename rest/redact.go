@@ -0,0 +1,93 @@
+package rest
+
+import (
+	"log"
+)
+
+// InternalDetailsPolicy 生产环境下内部错误详情的处理方式。
+type InternalDetailsPolicy int
+
+const (
+	// InternalDetailsIncidentID 错误详情替换为事件ID，完整错误记录在服务端日志中
+	InternalDetailsIncidentID InternalDetailsPolicy = iota
+	// InternalDetailsStrip 去掉错误详情，完整错误记录在服务端日志中
+	InternalDetailsStrip
+)
+
+var (
+	// ProductionInternalDetails 生产环境下内部错误详情的处理方式
+	ProductionInternalDetails = InternalDetailsIncidentID
+
+	// DetailsRedactor 响应前对 ErrorDetails 的脱敏处理，为 nil 时不处理
+	DetailsRedactor func(details interface{}) interface{}
+)
+
+// internalErrorDetails 生成非 HTTPError 的错误详情，生产环境下不返回内部细节。
+func internalErrorDetails(err error) interface{} {
+	if !IsProduction() {
+		return err.Error()
+	}
+
+	switch ProductionInternalDetails {
+	case InternalDetailsStrip:
+		log.Printf("internal error: %v", err)
+		return nil
+	default:
+		incidentID := newRequestID()
+		log.Printf("internal error: incident_id=%s error=%v", incidentID, err)
+		return map[string]interface{}{
+			"incident_id": incidentID,
+		}
+	}
+}
+
+// redactDetails 使用 DetailsRedactor 处理错误详情。
+func redactDetails(details interface{}) interface{} {
+	if DetailsRedactor == nil || details == nil {
+		return details
+	}
+	return DetailsRedactor(details)
+}
+
+// RedactKeys 返回一个 DetailsRedactor，将错误详情中指定key的值替换为 ***，支持嵌套的 map 和切片。
+// 错误详情先按 JSON 序列化规则转换为通用的 map 和切片，因此结构体字段按 json tag 匹配；无法序列化时整体替换为 ***。
+func RedactKeys(keys ...string) func(details interface{}) interface{} {
+	redacted := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		redacted[k] = true
+	}
+
+	var redact func(v interface{}) interface{}
+	redact = func(v interface{}) interface{} {
+		switch data := v.(type) {
+		case map[string]interface{}:
+			m := make(map[string]interface{}, len(data))
+			for k, item := range data {
+				if redacted[k] {
+					m[k] = "***"
+					continue
+				}
+				m[k] = redact(item)
+			}
+			return m
+		case []interface{}:
+			items := make([]interface{}, len(data))
+			for i, item := range data {
+				items[i] = redact(item)
+			}
+			return items
+		}
+		return v
+	}
+	return func(details interface{}) interface{} {
+		if details == nil {
+			return nil
+		}
+		generic, err := toGeneric(details)
+		if err != nil {
+			log.Printf("redact error details failed: %v", err)
+			return "***"
+		}
+		return redact(generic)
+	}
+}
@@ -0,0 +1,91 @@
+package rest_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/RockyRori/AdoLib/rest"
+	"github.com/RockyRori/AdoLib/rest/resttest"
+	"github.com/gin-gonic/gin"
+)
+
+func TestRedactKeys(t *testing.T) {
+	type credential struct {
+		User     string `json:"user"`
+		Password string `json:"password"`
+	}
+	details := map[string]interface{}{
+		"login":   credential{User: "rocky", Password: "secret"},
+		"headers": map[string]string{"token": "abc", "accept": "json"},
+		"items":   []credential{{User: "a", Password: "b"}},
+	}
+
+	got := rest.RedactKeys("password", "token")(details)
+	want := map[string]interface{}{
+		"login":   map[string]interface{}{"user": "rocky", "password": "***"},
+		"headers": map[string]interface{}{"token": "***", "accept": "json"},
+		"items":   []interface{}{map[string]interface{}{"user": "a", "password": "***"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RedactKeys = %v, want %v", got, want)
+	}
+}
+
+func TestReplyErrorProductionDetails(t *testing.T) {
+	setMode(t, rest.ModeProduction)
+	oldPolicy := rest.ProductionInternalDetails
+	t.Cleanup(func() { rest.ProductionInternalDetails = oldPolicy })
+
+	const secret = "dial tcp 10.0.0.3:5432: password authentication failed"
+	engine := resttest.NewEngine(resttest.Route{
+		Method: http.MethodGet,
+		Path:   "/users",
+		Handlers: []gin.HandlerFunc{func(c *gin.Context) {
+			rest.ReplyError(c, errors.New(secret))
+		}},
+	})
+
+	t.Run("incident id", func(t *testing.T) {
+		rest.ProductionInternalDetails = rest.InternalDetailsIncidentID
+
+		resp := resttest.Do(t, engine, resttest.Request{Method: http.MethodGet, Path: "/users"})
+		resp.AssertStatus(t, http.StatusInternalServerError).AssertErrorCode(t, rest.InternalError)
+		if strings.Contains(resp.Body.String(), secret) {
+			t.Errorf("body should not contain the internal error: %s", resp.Body.String())
+		}
+
+		var body struct {
+			ErrorDetails map[string]interface{} `json:"error_details"`
+		}
+		if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		incidentID, _ := body.ErrorDetails["incident_id"].(string)
+		if len(body.ErrorDetails) != 1 || len(incidentID) != 32 {
+			t.Errorf("error_details = %v, want only an incident_id", body.ErrorDetails)
+		}
+	})
+
+	t.Run("strip", func(t *testing.T) {
+		rest.ProductionInternalDetails = rest.InternalDetailsStrip
+
+		resp := resttest.Do(t, engine, resttest.Request{Method: http.MethodGet, Path: "/users"})
+		resp.AssertStatus(t, http.StatusInternalServerError).
+			AssertErrorCode(t, rest.InternalError).
+			AssertErrorDetails(t, nil)
+		if strings.Contains(resp.Body.String(), secret) {
+			t.Errorf("body should not contain the internal error: %s", resp.Body.String())
+		}
+	})
+
+	t.Run("development", func(t *testing.T) {
+		setMode(t, rest.ModeDevelopment)
+
+		resttest.Do(t, engine, resttest.Request{Method: http.MethodGet, Path: "/users"}).
+			AssertErrorDetails(t, secret)
+	})
+}
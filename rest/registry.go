@@ -0,0 +1,247 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+
+	. "github.com/RockyRori/AdoLib/i18n"
+)
+
+// UnknownCodeBehavior 创建未注册错误码的 HTTPError 时的处理方式。
+type UnknownCodeBehavior int
+
+const (
+	// UnknownCodeFatal 直接退出进程
+	UnknownCodeFatal UnknownCodeBehavior = iota
+	// UnknownCodeFallback 记录告警日志，返回 InternalError，并在错误详情中带上原始错误码
+	UnknownCodeFallback
+)
+
+// ErrorRegistry 错误码注册表，支持并发使用。
+type ErrorRegistry struct {
+	mu                  sync.RWMutex
+	errs                map[string]map[string]BaseError
+	defs                map[string]ErrorDefinition
+	builtins            map[string]bool                 // 仍使用系统默认文案的错误码
+	raw                 map[string]map[string]BaseError // 注册错误码未渲染的文案，用于生成文档
+	unknownCodeBehavior UnknownCodeBehavior
+	namingScheme        *NamingScheme
+}
+
+// DefaultRegistry 默认的错误码注册表，Register 和 NewHTTPError 使用此注册表。
+var DefaultRegistry = NewErrorRegistry()
+
+// NewErrorRegistry 创建错误码注册表，包含系统默认错误。
+func NewErrorRegistry() *ErrorRegistry {
+	errs := make(map[string]map[string]BaseError, len(commonErrorI18n))
+	builtins := make(map[string]bool, len(commonErrorI18n))
+	for errorCode, langErrs := range commonErrorI18n {
+		builtins[errorCode] = true
+		errs[errorCode] = make(map[string]BaseError, len(langErrs))
+		for lang, baseErr := range langErrs {
+			baseErr.Description, _ = renderCommonError(errorCode, lang, "Description", nil)
+			baseErr.Solution, _ = renderCommonError(errorCode, lang, "Solution", nil)
+			errs[errorCode][lang] = baseErr
+		}
+	}
+
+	defs := make(map[string]ErrorDefinition, len(commonErrorDefinitions))
+	for _, def := range commonErrorDefinitions {
+		defs[def.Code] = def
+	}
+
+	return &ErrorRegistry{
+		errs:                errs,
+		defs:                defs,
+		builtins:            builtins,
+		raw:                 make(map[string]map[string]BaseError),
+		unknownCodeBehavior: UnknownCodeFatal,
+	}
+}
+
+// SetUnknownCodeBehavior 设置未注册错误码的处理方式。
+func (r *ErrorRegistry) SetUnknownCodeBehavior(behavior UnknownCodeBehavior) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.unknownCodeBehavior = behavior
+}
+
+// Register 注册错误码，错误码重复或缺少翻译时返回错误，此时不注册任何错误码。
+// 系统默认错误码（例如 NotFound）可以注册一次，语言文件中的文案会覆盖默认文案。
+func (r *ErrorRegistry) Register(errorCodeList []string) error {
+	newErrs := make(map[string]map[string]BaseError, len(errorCodeList))
+	newRaw := make(map[string]map[string]BaseError, len(errorCodeList))
+	for _, errorCode := range errorCodeList {
+		if _, ok := newErrs[errorCode]; ok {
+			return fmt.Errorf("duplicate errorCode: %s", errorCode)
+		}
+		if err := r.validateCode(errorCode); err != nil {
+			return err
+		}
+
+		newErrs[errorCode] = make(map[string]BaseError, len(Languages))
+		newRaw[errorCode] = make(map[string]BaseError, len(Languages))
+		for lang := range Languages {
+			baseErr, err := translateBaseError(lang, errorCode)
+			if err != nil {
+				return err
+			}
+			newErrs[errorCode][lang] = baseErr
+
+			raw := baseErr
+			raw.Description, _ = MessageData(lang, errorCode+".Description")
+			raw.Solution, _ = MessageData(lang, errorCode+".Solution")
+			newRaw[errorCode][lang] = raw
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for errorCode := range newErrs {
+		if _, ok := r.errs[errorCode]; ok && !r.builtins[errorCode] {
+			return fmt.Errorf("duplicate errorCode: %s", errorCode)
+		}
+	}
+	for errorCode, langErrs := range newErrs {
+		if r.builtins[errorCode] {
+			log.Printf("errorCode %s overrides the built-in error", errorCode)
+			delete(r.builtins, errorCode)
+		}
+		r.errs[errorCode] = langErrs
+		r.raw[errorCode] = newRaw[errorCode]
+	}
+	return nil
+}
+
+// isBuiltin 判断错误码是否仍使用系统默认文案。
+func (r *ErrorRegistry) isBuiltin(errorCode string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.builtins[errorCode]
+}
+
+func translateBaseError(lang string, errorCode string) (BaseError, error) {
+	var texts [3]string
+	for i, field := range []string{"Description", "Solution"} {
+		text, err := TranslateWithError(lang, errorCode+"."+field, nil)
+		if err != nil {
+			return BaseError{}, fmt.Errorf("errorCode %s: %v", errorCode, err)
+		}
+		texts[i] = text
+	}
+	// ErrorLink 可省略，省略时按 SetErrorLink 配置的模板生成
+	texts[2], _ = TranslateWithError(lang, errorCode+".ErrorLink", nil)
+
+	return BaseError{
+		ErrorCode:               errorCode,
+		Description:             texts[0],
+		Solution:                texts[1],
+		ErrorLink:               texts[2],
+		ErrorDetails:            "",
+		DescriptionTemplateData: make(map[string]interface{}),
+		SolutionTemplateData:    make(map[string]interface{}),
+	}, nil
+}
+
+// Lookup 获取错误码指定语言的错误信息。
+func (r *ErrorRegistry) Lookup(errorCode string, lang string) (BaseError, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	errs, ok := r.errs[errorCode]
+	if !ok {
+		return BaseError{}, false
+	}
+	baseErr, ok := errs[lang]
+	return baseErr, ok
+}
+
+// lookupRaw 获取注册错误码未渲染的文案，系统默认错误返回 false。
+func (r *ErrorRegistry) lookupRaw(errorCode string, lang string) (BaseError, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	baseErr, ok := r.raw[errorCode][lang]
+	return baseErr, ok
+}
+
+// Codes 返回所有已注册的错误码，按字典序排列。
+func (r *ErrorRegistry) Codes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	codes := make([]string, 0, len(r.errs))
+	for errorCode := range r.errs {
+		codes = append(codes, errorCode)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// NewHTTPError 创建 HTTPError，错误码未注册时按 UnknownCodeBehavior 处理。
+func (r *ErrorRegistry) NewHTTPError(ctx context.Context, httpCode int, errorCode string) *HTTPError {
+	lang := GetLanguageByCtx(ctx)
+	tenant := GetTenantByCtx(ctx)
+
+	baseErr, ok := r.Lookup(errorCode, lang)
+	if !ok && lang != DefaultLanguage {
+		// 错误码缺少该语言的文案（例如系统默认错误只有 zh-CN 和 en-US）时使用默认语言
+		if baseErr, ok = r.Lookup(errorCode, DefaultLanguage); ok {
+			lang = DefaultLanguage
+		}
+	}
+	if !ok {
+		r.mu.RLock()
+		behavior := r.unknownCodeBehavior
+		r.mu.RUnlock()
+
+		if behavior == UnknownCodeFatal {
+			log.Fatalf("missing errorCode: %s, lang: %s", errorCode, lang)
+			return nil
+		}
+
+		if errorCode == InternalError {
+			// 回退的目标本身缺失，返回只有错误码的 InternalError，避免无限递归
+			log.Printf("missing errorCode: %s, lang: %s", errorCode, lang)
+			return &HTTPError{
+				HTTPCode:  http.StatusInternalServerError,
+				Language:  lang,
+				Tenant:    tenant,
+				Location:  GetLocationByCtx(ctx),
+				BaseError: BaseError{ErrorCode: InternalError},
+				registry:  r,
+			}
+		}
+
+		log.Printf("missing errorCode: %s, lang: %s, fallback to %s", errorCode, lang, InternalError)
+		return r.NewHTTPError(ctx, http.StatusInternalServerError, InternalError).
+			WithErrorDetails(map[string]interface{}{"unknown_error_code": errorCode})
+	}
+
+	return &HTTPError{
+		HTTPCode: httpCode,
+		Language: lang,
+		Tenant:   tenant,
+		Location: GetLocationByCtx(ctx),
+		BaseError: BaseError{
+			ErrorCode:    errorCode,
+			Description:  tenantText(tenant, lang, errorCode+".Description", baseErr.Description),
+			ErrorLink:    resolveErrorLink(errorCode, lang, tenantText(tenant, lang, errorCode+".ErrorLink", baseErr.ErrorLink)),
+			Solution:     tenantText(tenant, lang, errorCode+".Solution", baseErr.Solution),
+			ErrorDetails: baseErr.ErrorDetails,
+		},
+		registry: r,
+	}
+}
+
+// tenantText 租户覆盖了 messageId 时返回租户的内容，否则返回 text。
+func tenantText(tenant string, lang string, messageId string, text string) string {
+	if !HasTenantMessage(tenant, lang, messageId) {
+		return text
+	}
+	return TranslateTenant(tenant, lang, messageId, nil)
+}
@@ -0,0 +1,161 @@
+package rest_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/RockyRori/AdoLib/rest"
+	"github.com/RockyRori/AdoLib/rest/resttest"
+)
+
+func TestRegisterOverridesBuiltin(t *testing.T) {
+	resttest.LoadLocale(t, "testdata/override", rest.NotFound)
+
+	ctx := context.WithValue(context.Background(), rest.XLangKey, "en-US")
+	e := rest.NewHTTPError(ctx, http.StatusNotFound, rest.NotFound).
+		WithDescription(map[string]interface{}{"Resource": "user"})
+	if want := "Cannot find user"; e.BaseError.Description != want {
+		t.Errorf("description = %q, want %q", e.BaseError.Description, want)
+	}
+
+	if err := rest.DefaultRegistry.Register([]string{rest.NotFound}); err == nil {
+		t.Error("registering an overridden built-in code twice should fail")
+	}
+}
+
+func TestBuiltinTextsAgree(t *testing.T) {
+	// 语言文件定义了 NotFound 但未注册时，NewHTTPError 和 WithDescription 都使用系统默认文案
+	resttest.LoadLocale(t, "testdata/override")
+
+	ctx := context.WithValue(context.Background(), rest.XLangKey, "en-US")
+	plain := rest.NewHTTPError(ctx, http.StatusNotFound, rest.NotFound)
+	withData := rest.NewHTTPError(ctx, http.StatusNotFound, rest.NotFound).WithDescription(nil)
+	if plain.BaseError.Description != withData.BaseError.Description {
+		t.Errorf("NewHTTPError description %q differs from WithDescription %q",
+			plain.BaseError.Description, withData.BaseError.Description)
+	}
+}
+
+func TestRegisterErrors(t *testing.T) {
+	resttest.LoadLocale(t, "testdata/locale")
+
+	registry := rest.NewErrorRegistry()
+	if err := registry.Register([]string{"DemoUserNotFound", "DemoUserNotFound"}); err == nil {
+		t.Error("duplicate codes in one call should fail")
+	}
+	if err := registry.Register([]string{"DemoUserNotFound", "DemoMissingTranslation"}); err == nil {
+		t.Error("codes without translations should fail")
+	}
+	if _, ok := registry.Lookup("DemoUserNotFound", "en-US"); ok {
+		t.Error("a failed Register should not register any code")
+	}
+
+	if err := registry.Register([]string{"DemoUserNotFound"}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := registry.Register([]string{"DemoUserNotFound"}); err == nil {
+		t.Error("registering a code twice should fail")
+	}
+}
+
+func TestUnknownCodeFallback(t *testing.T) {
+	registry := rest.NewErrorRegistry()
+	registry.SetUnknownCodeBehavior(rest.UnknownCodeFallback)
+
+	ctx := context.WithValue(context.Background(), rest.XLangKey, "en-US")
+	e := registry.NewHTTPError(ctx, http.StatusNotFound, "MissingCode")
+	if e.HTTPCode != http.StatusInternalServerError || e.BaseError.ErrorCode != rest.InternalError {
+		t.Errorf("unknown code should fall back to 500 %s, got %d %s", rest.InternalError, e.HTTPCode, e.BaseError.ErrorCode)
+	}
+	want := map[string]interface{}{"unknown_error_code": "MissingCode"}
+	if !reflect.DeepEqual(e.BaseError.ErrorDetails, want) {
+		t.Errorf("error_details = %v, want %v", e.BaseError.ErrorDetails, want)
+	}
+}
+
+// TestRegistryConcurrent 并发注册和创建错误，需配合 -race 运行。
+func TestRegistryConcurrent(t *testing.T) {
+	resttest.LoadLocale(t, "testdata/locale")
+
+	registry := rest.NewErrorRegistry()
+	ctx := context.WithValue(context.Background(), rest.XLangKey, "zh-CN")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			// 只有一次注册成功，其余返回重复错误
+			_ = registry.Register([]string{"DemoUserNotFound"})
+		}()
+		go func(i int) {
+			defer wg.Done()
+			e := registry.NewHTTPError(ctx, http.StatusNotFound, rest.NotFound).
+				WithDescription(map[string]interface{}{"Resource": fmt.Sprint(i)})
+			if want := fmt.Sprintf("%d不存在", i); e.BaseError.Description != want {
+				t.Errorf("description = %q, want %q", e.BaseError.Description, want)
+			}
+			registry.Codes()
+		}(i)
+	}
+	wg.Wait()
+
+	if _, ok := registry.Lookup("DemoUserNotFound", "zh-CN"); !ok {
+		t.Error("DemoUserNotFound should be registered")
+	}
+}
+
+func addLanguage(t *testing.T, lang string) {
+	t.Helper()
+
+	oldDefault := rest.DefaultLanguage
+	rest.Languages[lang] = lang
+	t.Cleanup(func() {
+		delete(rest.Languages, lang)
+		rest.DefaultLanguage = oldDefault
+	})
+}
+
+func TestNewHTTPErrorMissingLanguage(t *testing.T) {
+	addLanguage(t, "ja-JP")
+
+	registry := rest.NewErrorRegistry()
+	registry.SetUnknownCodeBehavior(rest.UnknownCodeFallback)
+	ctx := context.WithValue(context.Background(), rest.XLangKey, "ja-JP")
+
+	// 系统默认错误没有 ja-JP 文案，使用默认语言
+	e := registry.NewHTTPError(ctx, http.StatusNotFound, rest.NotFound).
+		WithDescription(map[string]interface{}{"Resource": "user"})
+	if e.Language != rest.DefaultLanguage || e.BaseError.Description != "user不存在" {
+		t.Errorf("NotFound = %s %q, want %s %q", e.Language, e.BaseError.Description, rest.DefaultLanguage, "user不存在")
+	}
+
+	e = registry.NewHTTPError(ctx, http.StatusNotFound, "MissingCode")
+	if e.BaseError.ErrorCode != rest.InternalError || e.BaseError.Description != "内部错误" {
+		t.Errorf("unknown code = %s %q, want %s %q", e.BaseError.ErrorCode, e.BaseError.Description, rest.InternalError, "内部错误")
+	}
+
+	lazy := registry.NewLazyHTTPError(http.StatusNotFound, "MissingCode").LocalizeLang("ja-JP")
+	if lazy.BaseError.ErrorCode != rest.InternalError || lazy.BaseError.Description != "内部错误" {
+		t.Errorf("lazy unknown code = %s %q", lazy.BaseError.ErrorCode, lazy.BaseError.Description)
+	}
+}
+
+func TestNewHTTPErrorMissingInternalError(t *testing.T) {
+	addLanguage(t, "ja-JP")
+	rest.SetLang("ja-JP")
+
+	registry := rest.NewErrorRegistry()
+	registry.SetUnknownCodeBehavior(rest.UnknownCodeFallback)
+	ctx := context.WithValue(context.Background(), rest.XLangKey, "ja-JP")
+
+	// 默认语言也没有 InternalError 时不能无限回退
+	e := registry.NewHTTPError(ctx, http.StatusNotFound, "MissingCode")
+	if e.HTTPCode != http.StatusInternalServerError || e.BaseError.ErrorCode != rest.InternalError {
+		t.Errorf("unknown code = %d %s, want 500 %s", e.HTTPCode, e.BaseError.ErrorCode, rest.InternalError)
+	}
+}
@@ -0,0 +1,171 @@
+// Package resttest 提供测试 gin handler 和错误响应的辅助函数。
+package resttest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/RockyRori/AdoLib/i18n"
+	"github.com/RockyRori/AdoLib/rest"
+	"github.com/gin-gonic/gin"
+)
+
+// Route 测试路由。
+type Route struct {
+	Method   string
+	Path     string
+	Handlers []gin.HandlerFunc
+}
+
+// NewEngine 创建测试模式的 gin engine，并注册指定路由。
+func NewEngine(routes ...Route) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	for _, route := range routes {
+		engine.Handle(route.Method, route.Path, route.Handlers...)
+	}
+	return engine
+}
+
+// Request 测试请求。
+type Request struct {
+	Method   string
+	Path     string
+	Language string            // X-Language，为空时不设置
+	Headers  map[string]string // 其他请求header
+	Body     interface{}       // 请求体，[]byte 和 string 原样发送，其余类型序列化为JSON
+}
+
+// Response 测试响应。
+type Response struct {
+	*httptest.ResponseRecorder
+}
+
+// Do 向 handler 发送请求并返回响应。
+func Do(t testing.TB, handler http.Handler, req Request) *Response {
+	t.Helper()
+
+	var body io.Reader
+	switch b := req.Body.(type) {
+	case nil:
+	case []byte:
+		body = bytes.NewReader(b)
+	case string:
+		body = bytes.NewBufferString(b)
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			t.Fatalf("marshal request body failed: %v", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	httpReq := httptest.NewRequest(req.Method, req.Path, body)
+	if req.Body != nil {
+		httpReq.Header.Set(rest.ContentTypeKey, rest.ContentTypeJson)
+	}
+	if req.Language != "" {
+		httpReq.Header.Set(rest.XLangHeader, req.Language)
+	}
+	for k, v := range req.Headers {
+		httpReq.Header.Set(k, v)
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httpReq)
+	return &Response{ResponseRecorder: recorder}
+}
+
+// BaseError 将响应体解析为 BaseError。
+func (r *Response) BaseError(t testing.TB) rest.BaseError {
+	t.Helper()
+
+	var baseErr rest.BaseError
+	if err := json.Unmarshal(r.Body.Bytes(), &baseErr); err != nil {
+		t.Fatalf("unmarshal BaseError failed: %v, body: %s", err, r.Body.String())
+	}
+	return baseErr
+}
+
+// AssertStatus 断言HTTP状态码。
+func (r *Response) AssertStatus(t testing.TB, status int) *Response {
+	t.Helper()
+
+	if r.Code != status {
+		t.Errorf("status = %d, want %d, body: %s", r.Code, status, r.Body.String())
+	}
+	return r
+}
+
+// AssertErrorCode 断言错误码。
+func (r *Response) AssertErrorCode(t testing.TB, errorCode string) *Response {
+	t.Helper()
+
+	if got := r.BaseError(t).ErrorCode; got != errorCode {
+		t.Errorf("error_code = %q, want %q", got, errorCode)
+	}
+	return r
+}
+
+// AssertDescription 断言本地化的错误描述。
+func (r *Response) AssertDescription(t testing.TB, description string) *Response {
+	t.Helper()
+
+	if got := r.BaseError(t).Description; got != description {
+		t.Errorf("description = %q, want %q", got, description)
+	}
+	return r
+}
+
+// AssertErrorDetails 断言错误详情，details 序列化为JSON后与响应中的 error_details 比较。
+func (r *Response) AssertErrorDetails(t testing.TB, details interface{}) *Response {
+	t.Helper()
+
+	var got struct {
+		ErrorDetails interface{} `json:"error_details"`
+	}
+	if err := json.Unmarshal(r.Body.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal error_details failed: %v, body: %s", err, r.Body.String())
+	}
+
+	data, err := json.Marshal(details)
+	if err != nil {
+		t.Fatalf("marshal expected error_details failed: %v", err)
+	}
+	var want interface{}
+	if err = json.Unmarshal(data, &want); err != nil {
+		t.Fatalf("unmarshal expected error_details failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(got.ErrorDetails, want) {
+		t.Errorf("error_details = %v, want %v", got.ErrorDetails, want)
+	}
+	return r
+}
+
+// LoadLocale 在隔离的环境中加载测试语言文件，并将 errorCodeList 注册到新的 DefaultRegistry，加载或注册失败时终止测试。
+// 测试结束时恢复之前的语言文件和 DefaultRegistry。
+// LoadLocale 替换了全局的语言文件和 rest.DefaultRegistry，调用它的测试不能使用 t.Parallel。
+func LoadLocale(t testing.TB, localeDir string, errorCodeList ...string) {
+	t.Helper()
+
+	restore := i18n.Reset()
+	oldRegistry := rest.DefaultRegistry
+	t.Cleanup(func() {
+		rest.DefaultRegistry = oldRegistry
+		restore()
+	})
+
+	if err := i18n.LoadI18n(localeDir); err != nil {
+		t.Fatalf("load locale failed: %v", err)
+	}
+	rest.DefaultRegistry = rest.NewErrorRegistry()
+	if err := rest.DefaultRegistry.Register(errorCodeList); err != nil {
+		t.Fatalf("register error codes failed: %v", err)
+	}
+}
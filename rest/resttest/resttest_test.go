@@ -0,0 +1,78 @@
+package resttest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/RockyRori/AdoLib/rest"
+	"github.com/gin-gonic/gin"
+)
+
+func orderEngine() *gin.Engine {
+	return NewEngine(Route{
+		Method: http.MethodPost,
+		Path:   "/orders/:id",
+		Handlers: []gin.HandlerFunc{func(c *gin.Context) {
+			var body map[string]interface{}
+			if err := c.ShouldBindJSON(&body); err != nil {
+				rest.ReplyError(c, err)
+				return
+			}
+			err := rest.NewHTTPError(rest.GetLanguageCtx(c), http.StatusNotFound, "DemoOrderNotFound").
+				WithDescription(map[string]interface{}{"ID": c.Param("id")}).
+				WithErrorDetails(body)
+			rest.ReplyError(c, err)
+		}},
+	})
+}
+
+func TestDo(t *testing.T) {
+	LoadLocale(t, "testdata/locale", "DemoOrderNotFound")
+
+	for lang, description := range map[string]string{
+		"zh-CN": "订单42不存在",
+		"en-US": "Order 42 does not exist",
+	} {
+		Do(t, orderEngine(), Request{
+			Method:   http.MethodPost,
+			Path:     "/orders/42",
+			Language: lang,
+			Headers:  map[string]string{"X-Request-ID": "req-1"},
+			Body:     map[string]interface{}{"count": 1},
+		}).AssertStatus(t, http.StatusNotFound).
+			AssertErrorCode(t, "DemoOrderNotFound").
+			AssertDescription(t, description).
+			AssertErrorDetails(t, map[string]int{"count": 1})
+	}
+}
+
+func TestDoRawBody(t *testing.T) {
+	LoadLocale(t, "testdata/locale", "DemoOrderNotFound")
+
+	resp := Do(t, orderEngine(), Request{Method: http.MethodPost, Path: "/orders/42", Body: `{"count":`})
+	resp.AssertStatus(t, http.StatusInternalServerError).AssertErrorCode(t, rest.InternalError)
+	if got := resp.BaseError(t).RequestID; got == "" {
+		t.Error("request_id should be generated")
+	}
+}
+
+func TestLoadLocaleRestores(t *testing.T) {
+	registry := rest.DefaultRegistry
+
+	t.Run("load", func(t *testing.T) {
+		LoadLocale(t, "testdata/locale", "DemoOrderNotFound")
+		if rest.DefaultRegistry == registry {
+			t.Error("LoadLocale should replace DefaultRegistry")
+		}
+		if _, ok := rest.DefaultRegistry.Lookup("DemoOrderNotFound", "en-US"); !ok {
+			t.Error("DemoOrderNotFound should be registered")
+		}
+	})
+
+	if rest.DefaultRegistry != registry {
+		t.Error("DefaultRegistry should be restored after the test")
+	}
+	if _, ok := rest.DefaultRegistry.Lookup("DemoOrderNotFound", "en-US"); ok {
+		t.Error("DemoOrderNotFound should not leak out of the test")
+	}
+}
@@ -2,7 +2,6 @@ package rest
 
 import (
 	"context"
-	"encoding/json"
 	"log"
 	"net/http"
 
@@ -13,7 +12,10 @@ import (
 // golangci-lint 要求独立定义key的类型
 type key string
 
-const XLangKey key = "X-Language"
+const (
+	XLangKey   key = "X-Language"
+	XTenantKey key = "X-Tenant-ID"
+)
 
 const (
 	XLangHeader     = "X-Language"
@@ -21,38 +23,103 @@ const (
 	ContentTypeJson = "application/json"
 )
 
-// ReplyOK 响应成功。
-func ReplyOK(c *gin.Context, statusCode int, body interface{}) {
+// ReplyOption 响应的可选配置。
+type ReplyOption func(*replyOptions)
+
+type replyOptions struct {
+	collapseLocalized bool
+}
+
+// WithCollapseLocalized 将响应体中的 LocalizedString 字段收敛为请求语言的文本。
+// 其余字段与 encoding/json 的输出一致，包括 json tag 的 omitempty、string 选项和匿名结构体同名字段的取舍。
+func WithCollapseLocalized() ReplyOption {
+	return func(o *replyOptions) {
+		o.collapseLocalized = true
+	}
+}
+
+// ReplyOK 响应成功，响应体格式根据 Accept 协商。
+func ReplyOK(c *gin.Context, statusCode int, body interface{}, opts ...ReplyOption) {
+	var o replyOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	// 按 Accept 选择 JSON、XML 或 YAML，没有可接受的格式时响应 406
+	format, ok := negotiateBodyFormat(c.GetHeader("Accept"))
+	if !ok {
+		ReplyError(c, NewHTTPErrorByCode(GetLanguageCtx(c), NotAcceptable).
+			WithErrorDetails(map[string]interface{}{"accept": c.GetHeader("Accept")}))
+		return
+	}
+
 	var bodyStr string
 	if body != nil {
-		b, _ := json.Marshal(body)
+		if o.collapseLocalized {
+			body = collapseLocalized(body, GetLanguageByCtx(GetLanguageCtx(c)))
+		}
+		_, b, err := marshalBody(format, "response", body)
+		if err != nil {
+			ReplyError(c, err)
+			return
+		}
 		bodyStr = string(b)
 	}
-	c.Writer.Header().Set(ContentTypeKey, ContentTypeJson)
+	c.Writer.Header().Set(ContentTypeKey, formatContentTypes[format])
 	c.String(statusCode, bodyStr)
 }
 
-func ReplyOkWithHeaders(c *gin.Context, statusCode int, body interface{}, headers map[string]string) {
+func ReplyOkWithHeaders(c *gin.Context, statusCode int, body interface{}, headers map[string]string, opts ...ReplyOption) {
 	addHeaders(c, headers)
-	ReplyOK(c, statusCode, body)
+	ReplyOK(c, statusCode, body, opts...)
 }
 
-// ReplyError 响应错误。
+// ReplyError 响应错误，响应体格式根据 Accept 协商，没有可接受的格式时使用 JSON。
 func ReplyError(c *gin.Context, err error) {
-	var statusCode int
-	var body string
+	// 在错误链中查找最外层的 HTTPError 或 MultiError，都找不到时作为内部错误处理
+	httpErr, multiErr := findReplyError(err)
+	if multiErr != nil {
+		replyMultiError(c, multiErr)
+		return
+	}
+	if httpErr == nil {
+		ctx := GetLanguageCtx(c)
+		httpErr = NewHTTPError(ctx, http.StatusInternalServerError, InternalError).WithErrorDetails(internalErrorDetails(err)).Wrap(err)
+	}
+	if httpErr.IsLazy() {
+		httpErr = httpErr.Localize(GetLanguageCtx(c))
+	}
+
+	httpErr = traceError(c, httpErr)
+	httpErr.BaseError.ErrorDetails = redactDetails(httpErr.BaseError.ErrorDetails)
+	contentType, body := renderError(c, httpErr)
+	c.Writer.Header().Set(ContentTypeKey, contentType)
+	c.String(httpErr.HTTPCode, body)
+}
+
+// findReplyError 按 errors.As 的顺序在错误链中查找第一个 HTTPError 或 MultiError，
+// 例如包装了批量错误的 HTTPError 按 HTTPError 响应。
+func findReplyError(err error) (*HTTPError, *MultiError) {
 	switch e := err.(type) {
+	case nil:
+		return nil, nil
 	case *HTTPError:
-		statusCode = e.HTTPCode
-		body = e.Error()
-	default:
-		statusCode = http.StatusInternalServerError
-		ctx := GetLanguageCtx(c)
-		body = NewHTTPError(ctx, statusCode, InternalError).WithErrorDetails(e.Error()).Error()
+		return e, nil
+	case *MultiError:
+		return nil, e
 	}
 
-	c.Writer.Header().Set(ContentTypeKey, ContentTypeJson)
-	c.String(statusCode, body)
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		return findReplyError(u.Unwrap())
+	case interface{ Unwrap() []error }:
+		for _, inner := range u.Unwrap() {
+			if httpErr, multiErr := findReplyError(inner); httpErr != nil || multiErr != nil {
+				return httpErr, multiErr
+			}
+		}
+	}
+	return nil, nil
 }
 
 func ReplyErrorWithHeaders(c *gin.Context, err error, headers map[string]string) {
@@ -69,18 +136,25 @@ func addHeaders(c *gin.Context, headers map[string]string) {
 }
 
 func GetLanguageCtx(c *gin.Context) context.Context {
+	ctx := withTimezoneName(c.Request.Context(), c.GetHeader(XTimezoneHeader))
+
+	// LanguageMiddleware 已解析语言时直接使用
+	if lang, _ := ctx.Value(XLangKey).(string); lang != "" {
+		return ctx
+	}
+
 	langStr := c.GetHeader(XLangHeader)
 	if langStr == "" {
-		return context.WithValue(c.Request.Context(), XLangKey, "")
+		return context.WithValue(ctx, XLangKey, "")
 	}
 
 	tags, _, err := language.ParseAcceptLanguage(langStr)
 	if tags == nil || len(tags) != 1 || err != nil {
 		log.Printf("invalid lang: %s", langStr)
-		return context.WithValue(c.Request.Context(), XLangKey, "")
+		return context.WithValue(ctx, XLangKey, "")
 	}
 
-	return context.WithValue(c.Request.Context(), XLangKey, tags[0].String())
+	return context.WithValue(ctx, XLangKey, tags[0].String())
 }
 
 func GetLanguageByCtx(ctx context.Context) string {
@@ -94,3 +168,14 @@ func GetLanguageByCtx(ctx context.Context) string {
 	}
 	return lang
 }
+
+// WithTenant 将租户ID写入 context，用于获取租户覆盖的国际化内容。
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, XTenantKey, tenant)
+}
+
+// GetTenantByCtx 从 context 获取租户ID，未设置时返回空字符串。
+func GetTenantByCtx(ctx context.Context) string {
+	tenant, _ := ctx.Value(XTenantKey).(string)
+	return tenant
+}
@@ -0,0 +1,234 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	ContentTypeNDJson      = "application/x-ndjson"
+	ContentTypeEventStream = "text/event-stream"
+)
+
+// StreamSource 流式数据源，返回下一个条目；没有更多条目时 ok 为 false；生产失败时返回 err。
+type StreamSource[T any] func(ctx context.Context) (item T, ok bool, err error)
+
+// FromChannel 将 channel 转换为 StreamSource，items 关闭时结束，errs 可为 nil，收到错误时结束。
+func FromChannel[T any](items <-chan T, errs <-chan error) StreamSource[T] {
+	return func(ctx context.Context) (item T, ok bool, err error) {
+		for {
+			select {
+			case <-ctx.Done():
+				return item, false, nil
+			case e, open := <-errs:
+				if !open {
+					// errs 已关闭，之后只等待条目
+					errs = nil
+					continue
+				}
+				if e != nil {
+					return item, false, e
+				}
+			case item, ok = <-items:
+				if !ok && errs != nil {
+					// items 关闭时 errs 中可能已有错误，select 随机选择会丢失该错误
+					select {
+					case e := <-errs:
+						if e != nil {
+							return item, false, e
+						}
+					default:
+					}
+				}
+				return item, ok, nil
+			}
+		}
+	}
+}
+
+// FromSlice 将切片转换为 StreamSource。
+func FromSlice[T any](items []T) StreamSource[T] {
+	i := 0
+	return func(ctx context.Context) (item T, ok bool, err error) {
+		if i >= len(items) {
+			return item, false, nil
+		}
+		item = items[i]
+		i++
+		return item, true, nil
+	}
+}
+
+// StreamOptions 流式响应配置。
+type StreamOptions struct {
+	FlushEvery    int           // 每写入多少个条目刷新一次，默认 1
+	FlushInterval time.Duration // 每隔该时间刷新未刷新的条目，等待生产者时也会刷新；为 0 时只按 FlushEvery 刷新
+	Event         string        // SSE 事件名，为空时使用 message
+}
+
+// streamWriter 按配置刷新的写入器。
+type streamWriter struct {
+	c       *gin.Context
+	opts    StreamOptions
+	pending int
+}
+
+func newStreamWriter(c *gin.Context, contentType string, opts StreamOptions) *streamWriter {
+	if opts.FlushEvery <= 0 {
+		opts.FlushEvery = 1
+	}
+
+	c.Writer.Header().Set(ContentTypeKey, contentType)
+	c.Writer.Header().Set("Cache-Control", "no-cache")
+	c.Writer.WriteHeader(http.StatusOK)
+	// 立即发送响应头，客户端无需等待第一个条目
+	c.Writer.Flush()
+	return &streamWriter{
+		c:    c,
+		opts: opts,
+	}
+}
+
+// written 写入一个条目后按配置刷新。
+func (w *streamWriter) written() {
+	w.pending++
+	if w.pending >= w.opts.FlushEvery {
+		w.flush()
+	}
+}
+
+func (w *streamWriter) flush() {
+	w.c.Writer.Flush()
+	w.pending = 0
+}
+
+type streamResult[T any] struct {
+	item T
+	ok   bool
+	err  error
+}
+
+// runStream 在独立的 goroutine 中调用 source，写入和刷新都在当前 goroutine 中进行，
+// 因此生产者阻塞时仍能按 FlushInterval 刷新已写入的条目。
+// 返回时取消传给 source 的 context，source 应在 context 取消后尽快返回。
+func runStream[T any](c *gin.Context, w *streamWriter, source StreamSource[T], write func(item T) error, fail func(err error)) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	results := make(chan streamResult[T])
+	go func() {
+		for {
+			item, ok, err := source(ctx)
+			select {
+			case results <- streamResult[T]{item: item, ok: ok, err: err}:
+			case <-ctx.Done():
+				return
+			}
+			if !ok || err != nil {
+				return
+			}
+		}
+	}()
+
+	var tick <-chan time.Time
+	if w.opts.FlushInterval > 0 {
+		ticker := time.NewTicker(w.opts.FlushInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick:
+			if w.pending > 0 {
+				w.flush()
+			}
+		case r := <-results:
+			if r.err != nil {
+				fail(r.err)
+				return
+			}
+			if !r.ok {
+				return
+			}
+			if err := write(r.item); err != nil {
+				log.Println(err.Error())
+				return
+			}
+			w.written()
+		}
+	}
+}
+
+// streamError 将生产者的错误转换为按请求语言本地化的 BaseError。
+func streamError(c *gin.Context, err error) BaseError {
+	ctx := GetLanguageCtx(c)
+
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) {
+		httpErr = NewHTTPError(ctx, http.StatusInternalServerError, InternalError).WithErrorDetails(internalErrorDetails(err)).Wrap(err)
+	}
+	if httpErr.IsLazy() {
+		httpErr = httpErr.Localize(ctx)
+	}
+
+	httpErr = traceError(c, httpErr)
+	httpErr.BaseError.ErrorDetails = redactDetails(httpErr.BaseError.ErrorDetails)
+	return httpErr.BaseError
+}
+
+// StreamNDJSON 以 NDJSON 格式流式响应，每个条目一行 JSON。
+// 客户端断开时停止；生产者失败时写入最后一行 {"error": BaseError}，错误按请求语言本地化。
+func StreamNDJSON[T any](c *gin.Context, source StreamSource[T], opts StreamOptions) {
+	w := newStreamWriter(c, ContentTypeNDJson, opts)
+	enc := json.NewEncoder(c.Writer)
+	defer w.flush()
+
+	runStream(c, w, source, func(item T) error {
+		return enc.Encode(item)
+	}, func(err error) {
+		if encErr := enc.Encode(map[string]interface{}{"error": streamError(c, err)}); encErr != nil {
+			log.Println(encErr.Error())
+		}
+	})
+}
+
+// StreamSSE 以 server-sent events 格式流式响应，每个条目一个事件，事件ID为条目序号。
+// 客户端断开时停止；生产者失败时发送 error 事件，内容为按请求语言本地化的 BaseError。
+func StreamSSE[T any](c *gin.Context, source StreamSource[T], opts StreamOptions) {
+	if opts.Event == "" {
+		opts.Event = "message"
+	}
+	c.Writer.Header().Set("Connection", "keep-alive")
+	w := newStreamWriter(c, ContentTypeEventStream, opts)
+	defer w.flush()
+
+	id := 0
+	runStream(c, w, source, func(item T) error {
+		err := sse.Encode(c.Writer, sse.Event{
+			Id:    strconv.Itoa(id),
+			Event: opts.Event,
+			Data:  item,
+		})
+		id++
+		return err
+	}, func(err error) {
+		encErr := sse.Encode(c.Writer, sse.Event{
+			Event: "error",
+			Data:  streamError(c, err),
+		})
+		if encErr != nil {
+			log.Println(encErr.Error())
+		}
+	})
+}
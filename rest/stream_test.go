@@ -0,0 +1,125 @@
+package rest_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/RockyRori/AdoLib/rest"
+	"github.com/RockyRori/AdoLib/rest/resttest"
+	"github.com/gin-gonic/gin"
+)
+
+func TestFromChannelBufferedError(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		items := make(chan int)
+		errs := make(chan error, 1)
+		errs <- errors.New("produce failed")
+		close(items)
+
+		_, ok, err := rest.FromChannel(items, errs)(context.Background())
+		if ok || err == nil {
+			t.Fatalf("FromChannel = ok %v, err %v, want buffered error", ok, err)
+		}
+	}
+}
+
+func TestStreamNDJSON(t *testing.T) {
+	n := 0
+	source := func(ctx context.Context) (int, bool, error) {
+		if n == 2 {
+			return 0, false, errors.New("produce failed")
+		}
+		n++
+		return n, true, nil
+	}
+
+	engine := resttest.NewEngine(resttest.Route{
+		Method: http.MethodGet,
+		Path:   "/stream",
+		Handlers: []gin.HandlerFunc{func(c *gin.Context) {
+			rest.StreamNDJSON(c, source, rest.StreamOptions{})
+		}},
+	})
+	resp := resttest.Do(t, engine, resttest.Request{Method: http.MethodGet, Path: "/stream", Language: "en-US"})
+	resp.AssertStatus(t, http.StatusOK)
+
+	if got := resp.Header().Get(rest.ContentTypeKey); got != rest.ContentTypeNDJson {
+		t.Errorf("Content-Type = %q, want %q", got, rest.ContentTypeNDJson)
+	}
+	lines := strings.Split(strings.TrimSpace(resp.Body.String()), "\n")
+	if len(lines) != 3 || lines[0] != "1" || lines[1] != "2" {
+		t.Fatalf("lines = %q, want 1, 2 and an error line", lines)
+	}
+	var last struct {
+		Error rest.BaseError `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(lines[2]), &last); err != nil {
+		t.Fatal(err)
+	}
+	if last.Error.ErrorCode != rest.InternalError {
+		t.Errorf("error_code = %q, want %q", last.Error.ErrorCode, rest.InternalError)
+	}
+}
+
+func TestStreamSSE(t *testing.T) {
+	engine := resttest.NewEngine(resttest.Route{
+		Method: http.MethodGet,
+		Path:   "/events",
+		Handlers: []gin.HandlerFunc{func(c *gin.Context) {
+			rest.StreamSSE(c, rest.FromSlice([]string{"a", "b"}), rest.StreamOptions{Event: "letter"})
+		}},
+	})
+	resp := resttest.Do(t, engine, resttest.Request{Method: http.MethodGet, Path: "/events"})
+	resp.AssertStatus(t, http.StatusOK)
+
+	want := "id:0\nevent:letter\ndata:a\n\nid:1\nevent:letter\ndata:b\n\n"
+	if got := resp.Body.String(); got != want {
+		t.Errorf("body = %q, want %q", got, want)
+	}
+}
+
+func TestStreamFlushInterval(t *testing.T) {
+	items := make(chan int)
+	engine := resttest.NewEngine(resttest.Route{
+		Method: http.MethodGet,
+		Path:   "/stream",
+		Handlers: []gin.HandlerFunc{func(c *gin.Context) {
+			rest.StreamNDJSON(c, rest.FromChannel(items, nil), rest.StreamOptions{
+				FlushEvery:    100,
+				FlushInterval: 10 * time.Millisecond,
+			})
+		}},
+	})
+	server := httptest.NewServer(engine)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/stream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	// 生产者写入一个条目后阻塞，条目仍应按 FlushInterval 刷新到客户端
+	items <- 1
+	lines := make(chan string, 1)
+	go func() {
+		line, _ := bufio.NewReader(resp.Body).ReadString('\n')
+		lines <- line
+	}()
+	select {
+	case line := <-lines:
+		if line != "1\n" {
+			t.Errorf("line = %q, want %q", line, "1\n")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("item was not flushed while the producer was blocked")
+	}
+	close(items)
+}
@@ -0,0 +1,50 @@
+package rest_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/RockyRori/AdoLib/i18n"
+	"github.com/RockyRori/AdoLib/rest"
+	"github.com/RockyRori/AdoLib/rest/resttest"
+)
+
+func TestNewHTTPErrorTenant(t *testing.T) {
+	resttest.LoadLocale(t, "testdata/locale", "DemoUserNotFound")
+	if err := i18n.LoadTenantI18n("acme", "testdata/tenant"); err != nil {
+		t.Fatal(err)
+	}
+
+	data := map[string]interface{}{"Name": "rocky"}
+	tests := []struct {
+		tenant      string
+		description string
+		solution    string
+		errorLink   string
+	}{
+		{
+			tenant:      "acme",
+			description: "Member rocky does not exist",
+			solution:    "Please check the user name",
+			errorLink:   "https://acme.example.com/en-US/user",
+		},
+		{
+			tenant:      "other",
+			description: "User rocky does not exist",
+			solution:    "Please check the user name",
+		},
+	}
+	for _, tt := range tests {
+		ctx := rest.WithTenant(context.WithValue(context.Background(), rest.XLangKey, "en-US"), tt.tenant)
+		if got := rest.GetTenantByCtx(ctx); got != tt.tenant {
+			t.Errorf("GetTenantByCtx = %q, want %q", got, tt.tenant)
+		}
+
+		e := rest.NewHTTPError(ctx, http.StatusNotFound, "DemoUserNotFound").WithDescription(data)
+		got := e.BaseError
+		if e.Tenant != tt.tenant || got.Description != tt.description || got.Solution != tt.solution || got.ErrorLink != tt.errorLink {
+			t.Errorf("tenant %s: %s %+v", tt.tenant, e.Tenant, got)
+		}
+	}
+}
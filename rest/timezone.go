@@ -0,0 +1,70 @@
+package rest
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	XTimezoneKey    key = "X-Timezone"
+	XTimezoneHeader     = "X-Timezone"
+)
+
+var (
+	// LanguageTimezones 语言默认时区，请求未指定时区时使用
+	LanguageTimezones = map[string]string{
+		"zh-CN": "Asia/Shanghai",
+	}
+
+	// 已加载时区的缓存，避免每次请求读取时区数据库
+	locationCache sync.Map
+)
+
+// WithTimezone 将时区写入 context。
+func WithTimezone(ctx context.Context, loc *time.Location) context.Context {
+	return context.WithValue(ctx, XTimezoneKey, loc)
+}
+
+// withTimezoneName 解析时区名称并写入 context，无效时区忽略。
+func withTimezoneName(ctx context.Context, name string) context.Context {
+	if name == "" {
+		return ctx
+	}
+
+	loc, err := loadLocation(name)
+	if err != nil {
+		log.Printf("invalid timezone: %s", name)
+		return ctx
+	}
+	return WithTimezone(ctx, loc)
+}
+
+// GetLocationByCtx 获取 context 中的时区，未指定时使用语言默认时区，都没有时使用服务端时区。
+func GetLocationByCtx(ctx context.Context) *time.Location {
+	if loc, ok := ctx.Value(XTimezoneKey).(*time.Location); ok && loc != nil {
+		return loc
+	}
+
+	if name, ok := LanguageTimezones[GetLanguageByCtx(ctx)]; ok {
+		if loc, err := loadLocation(name); err == nil {
+			return loc
+		}
+		log.Printf("invalid timezone %s of lang", name)
+	}
+	return time.Local
+}
+
+func loadLocation(name string) (*time.Location, error) {
+	if loc, ok := locationCache.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	locationCache.Store(name, loc)
+	return loc, nil
+}
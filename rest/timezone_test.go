@@ -0,0 +1,77 @@
+package rest_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/RockyRori/AdoLib/rest"
+	"github.com/RockyRori/AdoLib/rest/resttest"
+	"github.com/gin-gonic/gin"
+)
+
+func TestTimezoneRendering(t *testing.T) {
+	if _, err := time.LoadLocation("Asia/Tokyo"); err != nil {
+		t.Skipf("timezone database unavailable: %v", err)
+	}
+	resttest.LoadLocale(t, "testdata/locale", "DemoQuotaExceeded")
+
+	resetAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	engine := resttest.NewEngine(resttest.Route{
+		Method: http.MethodGet,
+		Path:   "/quota",
+		Handlers: []gin.HandlerFunc{func(c *gin.Context) {
+			err := rest.NewHTTPError(rest.GetLanguageCtx(c), http.StatusTooManyRequests, "DemoQuotaExceeded").
+				WithDescription(map[string]interface{}{"ResetAt": resetAt})
+			rest.ReplyError(c, err)
+		}},
+	})
+
+	tests := []struct {
+		name        string
+		lang        string
+		timezone    string
+		description string
+	}{
+		{name: "request timezone", lang: "en-US", timezone: "Asia/Tokyo", description: "Quota exceeded, resets at 09:00"},
+		{name: "language timezone", lang: "zh-CN", description: "配额已用完，将于 08:00 重置"},
+		{name: "request timezone over language timezone", lang: "zh-CN", timezone: "Asia/Tokyo", description: "配额已用完，将于 09:00 重置"},
+		{name: "invalid timezone falls back to language timezone", lang: "zh-CN", timezone: "Mars/Olympus", description: "配额已用完，将于 08:00 重置"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := map[string]string{}
+			if tt.timezone != "" {
+				headers[rest.XTimezoneHeader] = tt.timezone
+			}
+			resttest.Do(t, engine, resttest.Request{Method: http.MethodGet, Path: "/quota", Language: tt.lang, Headers: headers}).
+				AssertStatus(t, http.StatusTooManyRequests).
+				AssertDescription(t, tt.description)
+		})
+	}
+}
+
+func TestGetLocationByCtx(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("timezone database unavailable: %v", err)
+	}
+
+	zh := context.WithValue(context.Background(), rest.XLangKey, "zh-CN")
+	en := context.WithValue(context.Background(), rest.XLangKey, "en-US")
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{name: "explicit timezone", ctx: rest.WithTimezone(en, tokyo), want: "Asia/Tokyo"},
+		{name: "language timezone", ctx: zh, want: "Asia/Shanghai"},
+		{name: "server timezone", ctx: en, want: time.Local.String()},
+	}
+	for _, tt := range tests {
+		if got := rest.GetLocationByCtx(tt.ctx).String(); got != tt.want {
+			t.Errorf("%s: GetLocationByCtx = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
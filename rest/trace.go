@@ -0,0 +1,100 @@
+package rest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// requestIDKey 请求ID在 gin.Context 中的key
+const requestIDKey = "rest.request_id"
+
+// ErrorTraceOptions 错误响应中请求ID和 trace ID 的配置。
+type ErrorTraceOptions struct {
+	RequestIDHeader string // 请求ID的header，请求未携带时自动生成
+	TraceIDHeader   string // 响应 trace ID 的header
+	InBody          bool   // 是否写入响应体
+	InHeader        bool   // 是否写入响应header
+	Log             bool   // 是否记录每个错误响应
+
+	// LogFields 日志中记录的字段及顺序，可选值见 ErrorLogRecord.Format，为空时使用 DefaultErrorLogFields
+	LogFields []string
+	// LogHook 设置时代替默认日志输出，接收结构化的错误记录，可用于接入其他日志库
+	LogHook func(record ErrorLogRecord)
+}
+
+// ErrorTrace 错误响应中请求ID和 trace ID 的配置
+var ErrorTrace = ErrorTraceOptions{
+	RequestIDHeader: "X-Request-ID",
+	TraceIDHeader:   "X-Trace-ID",
+	InBody:          true,
+	InHeader:        true,
+	Log:             true,
+}
+
+// GetRequestID 获取请求ID，优先使用请求header中的值，没有时生成一个并在本次请求内复用。
+func GetRequestID(c *gin.Context) string {
+	if requestID := c.GetString(requestIDKey); requestID != "" {
+		return requestID
+	}
+
+	requestID := c.GetHeader(ErrorTrace.RequestIDHeader)
+	if requestID == "" {
+		requestID = newRequestID()
+	}
+	c.Set(requestIDKey, requestID)
+	return requestID
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		log.Println(err.Error())
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// GetTraceID 获取 context 中 span 的 trace ID，没有有效 span 时返回空字符串。
+func GetTraceID(ctx context.Context) string {
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if !spanCtx.HasTraceID() {
+		return ""
+	}
+	return spanCtx.TraceID().String()
+}
+
+// traceError 按 ErrorTrace 配置将请求ID和 trace ID 写入错误响应，并记录日志和指标。
+// 返回的 HTTPError 是副本，不修改调用方的错误对象。
+func traceError(c *gin.Context, e *HTTPError) *HTTPError {
+	requestID := GetRequestID(c)
+	traceID := GetTraceID(c.Request.Context())
+
+	reply := *e
+	if ErrorTrace.InBody {
+		reply.BaseError.RequestID = requestID
+		reply.BaseError.TraceID = traceID
+	}
+
+	setTraceHeaders(c, requestID, traceID)
+
+	observeError(c, e, requestID, traceID)
+	return &reply
+}
+
+// setTraceHeaders 按 ErrorTrace 配置将请求ID和 trace ID 写入响应header。
+func setTraceHeaders(c *gin.Context, requestID string, traceID string) {
+	if !ErrorTrace.InHeader {
+		return
+	}
+	if ErrorTrace.RequestIDHeader != "" && requestID != "" {
+		c.Writer.Header().Set(ErrorTrace.RequestIDHeader, requestID)
+	}
+	if ErrorTrace.TraceIDHeader != "" && traceID != "" {
+		c.Writer.Header().Set(ErrorTrace.TraceIDHeader, traceID)
+	}
+}
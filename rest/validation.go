@@ -0,0 +1,207 @@
+package rest
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	gotemplate "text/template"
+
+	. "github.com/RockyRori/AdoLib/i18n"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError 单个字段的校验错误。
+type FieldError struct {
+	Field   string `json:"field"`   // 字段名，优先使用绑定对应的 tag：query 和表单为 form，其余为 json
+	Rule    string `json:"rule"`    // 校验规则，例如 required、max
+	Param   string `json:"param"`   // 规则参数，例如 max=10 中的 10
+	Message string `json:"message"` // 本地化的错误信息
+}
+
+var (
+	// validationMessages 常用校验规则的默认错误信息，可通过语言文件中的 Validation.<rule> 覆盖
+	validationMessages = map[string]map[string]string{
+		"zh-CN": {
+			"required": "{{.Field}}为必填字段",
+			"min":      "{{.Field}}最小值或最小长度为{{.Param}}",
+			"max":      "{{.Field}}最大值或最大长度为{{.Param}}",
+			"len":      "{{.Field}}长度必须为{{.Param}}",
+			"eq":       "{{.Field}}必须等于{{.Param}}",
+			"ne":       "{{.Field}}不能等于{{.Param}}",
+			"gt":       "{{.Field}}必须大于{{.Param}}",
+			"gte":      "{{.Field}}必须大于或等于{{.Param}}",
+			"lt":       "{{.Field}}必须小于{{.Param}}",
+			"lte":      "{{.Field}}必须小于或等于{{.Param}}",
+			"oneof":    "{{.Field}}必须是[{{.Param}}]中的一个",
+			"email":    "{{.Field}}必须是有效的邮箱地址",
+			"url":      "{{.Field}}必须是有效的URL",
+			"uuid":     "{{.Field}}必须是有效的UUID",
+			"numeric":  "{{.Field}}必须是数字",
+			"alphanum": "{{.Field}}只能包含字母和数字",
+			"default":  "{{.Field}}不满足校验规则{{.Rule}}",
+		},
+		"en-US": {
+			"required": "{{.Field}} is required",
+			"min":      "{{.Field}} must be at least {{.Param}}",
+			"max":      "{{.Field}} must be at most {{.Param}}",
+			"len":      "{{.Field}} must have length {{.Param}}",
+			"eq":       "{{.Field}} must be equal to {{.Param}}",
+			"ne":       "{{.Field}} must not be equal to {{.Param}}",
+			"gt":       "{{.Field}} must be greater than {{.Param}}",
+			"gte":      "{{.Field}} must be greater than or equal to {{.Param}}",
+			"lt":       "{{.Field}} must be less than {{.Param}}",
+			"lte":      "{{.Field}} must be less than or equal to {{.Param}}",
+			"oneof":    "{{.Field}} must be one of [{{.Param}}]",
+			"email":    "{{.Field}} must be a valid email address",
+			"url":      "{{.Field}} must be a valid URL",
+			"uuid":     "{{.Field}} must be a valid UUID",
+			"numeric":  "{{.Field}} must be numeric",
+			"alphanum": "{{.Field}} must contain only letters and numbers",
+			"default":  "{{.Field}} failed on the {{.Rule}} rule",
+		},
+	}
+
+	validationTemplates = mustParseValidationMessages()
+)
+
+func mustParseValidationMessages() map[string]map[string]*gotemplate.Template {
+	templates := make(map[string]map[string]*gotemplate.Template, len(validationMessages))
+	for lang, messages := range validationMessages {
+		templates[lang] = make(map[string]*gotemplate.Template, len(messages))
+		for rule, message := range messages {
+			templates[lang][rule] = gotemplate.Must(gotemplate.New(rule).Parse(message))
+		}
+	}
+	return templates
+}
+
+// BindAndValidate 绑定并校验请求参数，失败时返回 400 ValidationError，错误详情为各字段本地化的 FieldError。
+func BindAndValidate(c *gin.Context, obj interface{}) error {
+	// 与 ShouldBind 选择相同的绑定，字段名使用该绑定读取的 tag
+	b := binding.Default(c.Request.Method, c.ContentType())
+	err := c.ShouldBindWith(obj, b)
+	if err == nil {
+		return nil
+	}
+
+	ctx := GetLanguageCtx(c)
+	httpErr := NewHTTPError(ctx, http.StatusBadRequest, ValidationError).Wrap(err)
+
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		// 请求体格式错误等非校验错误
+		return httpErr.WithErrorDetails(err.Error())
+	}
+
+	fieldErrs := make([]FieldError, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		fieldErrs = append(fieldErrs, newFieldError(httpErr.Language, reflect.TypeOf(obj), bindingTag(b), fe))
+	}
+	return httpErr.WithErrorDetails(fieldErrs)
+}
+
+func newFieldError(lang string, objType reflect.Type, tagName string, fe validator.FieldError) FieldError {
+	fieldErr := FieldError{
+		Field: fieldName(objType, tagName, fe),
+		Rule:  fe.Tag(),
+		Param: fe.Param(),
+	}
+
+	data := map[string]interface{}{
+		"Field": fieldErr.Field,
+		"Rule":  fieldErr.Rule,
+		"Param": fieldErr.Param,
+	}
+
+	// 语言文件中定义了 Validation.<rule> 时优先使用
+	if message, err := TranslateWithError(lang, "Validation."+fieldErr.Rule, data); err == nil {
+		fieldErr.Message = message
+		return fieldErr
+	}
+
+	templates, ok := validationTemplates[lang]
+	if !ok {
+		templates = validationTemplates[DefaultLanguage]
+	}
+	tmpl, ok := templates[fieldErr.Rule]
+	if !ok {
+		tmpl = templates["default"]
+	}
+	if tmpl == nil {
+		fieldErr.Message = fe.Error()
+		return fieldErr
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		fieldErr.Message = fe.Error()
+		return fieldErr
+	}
+	fieldErr.Message = buf.String()
+	return fieldErr
+}
+
+// bindingTag 返回绑定读取字段名的 tag，query 和表单使用 form，其余使用 json。
+func bindingTag(b binding.Binding) string {
+	switch b {
+	case binding.Form, binding.FormPost, binding.FormMultipart, binding.Query:
+		return "form"
+	}
+	return "json"
+}
+
+// fieldName 按 fe.StructNamespace() 在 objType 中查找出错的字段，返回其 tagName tag 中的名称，
+// 找不到字段或没有对应 tag 时返回 fe.Field()。
+func fieldName(objType reflect.Type, tagName string, fe validator.FieldError) string {
+	// 命名空间的第一段为顶层结构体的类型名
+	segments := strings.Split(fe.StructNamespace(), ".")
+	if objType == nil || len(segments) < 2 {
+		return fe.Field()
+	}
+
+	t := objType
+	for i, segment := range segments[1:] {
+		name, index := segment, ""
+		if n := strings.IndexByte(segment, '['); n >= 0 {
+			name, index = segment[:n], segment[n:]
+		}
+
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return fe.Field()
+		}
+		sf, ok := t.FieldByName(name)
+		if !ok {
+			return fe.Field()
+		}
+
+		if i == len(segments)-2 {
+			tagValue, _, _ := strings.Cut(sf.Tag.Get(tagName), ",")
+			if tagValue == "" || tagValue == "-" {
+				return fe.Field()
+			}
+			return tagValue + index
+		}
+
+		// 切片、数组和 map 的元素
+		t = sf.Type
+		for n := strings.Count(index, "["); n > 0; n-- {
+			for t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+			switch t.Kind() {
+			case reflect.Slice, reflect.Array, reflect.Map:
+				t = t.Elem()
+			default:
+				return fe.Field()
+			}
+		}
+	}
+	return fe.Field()
+}
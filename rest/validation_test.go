@@ -0,0 +1,90 @@
+package rest_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/RockyRori/AdoLib/rest"
+	"github.com/RockyRori/AdoLib/rest/resttest"
+	"github.com/gin-gonic/gin"
+)
+
+type createUserRequest struct {
+	Name    string `json:"user_name" binding:"required"`
+	Age     int    `json:"age" binding:"max=150"`
+	Address struct {
+		City string `json:"city" binding:"required"`
+	} `json:"address"`
+	Tags []struct {
+		Value string `json:"value" binding:"required"`
+	} `json:"tags" binding:"dive"`
+	Remark string `binding:"max=3"`
+}
+
+func TestBindAndValidate(t *testing.T) {
+	engine := resttest.NewEngine(resttest.Route{
+		Method: http.MethodPost,
+		Path:   "/users",
+		Handlers: []gin.HandlerFunc{func(c *gin.Context) {
+			var req createUserRequest
+			if err := rest.BindAndValidate(c, &req); err != nil {
+				rest.ReplyError(c, err)
+				return
+			}
+			c.Status(http.StatusNoContent)
+		}},
+	})
+
+	resp := resttest.Do(t, engine, resttest.Request{
+		Method:   http.MethodPost,
+		Path:     "/users",
+		Language: "en-US",
+		Body: map[string]interface{}{
+			"age":    200,
+			"tags":   []map[string]string{{"value": "a"}, {}},
+			"Remark": "long",
+		},
+	})
+	resp.AssertStatus(t, http.StatusBadRequest).
+		AssertErrorCode(t, rest.ValidationError).
+		AssertErrorDetails(t, []rest.FieldError{
+			{Field: "user_name", Rule: "required", Message: "user_name is required"},
+			{Field: "age", Rule: "max", Param: "150", Message: "age must be at most 150"},
+			{Field: "city", Rule: "required", Message: "city is required"},
+			{Field: "value", Rule: "required", Message: "value is required"},
+			{Field: "Remark", Rule: "max", Param: "3", Message: "Remark must be at most 3"},
+		})
+}
+
+type listUsersRequest struct {
+	Page     int    `form:"page" binding:"min=1"`
+	PageSize int    `form:"page_size" json:"pageSize" binding:"max=100"`
+	Keyword  string `binding:"required"`
+}
+
+func TestBindAndValidateQuery(t *testing.T) {
+	engine := resttest.NewEngine(resttest.Route{
+		Method: http.MethodGet,
+		Path:   "/users",
+		Handlers: []gin.HandlerFunc{func(c *gin.Context) {
+			var req listUsersRequest
+			if err := rest.BindAndValidate(c, &req); err != nil {
+				rest.ReplyError(c, err)
+				return
+			}
+			c.Status(http.StatusNoContent)
+		}},
+	})
+
+	resttest.Do(t, engine, resttest.Request{
+		Method:   http.MethodGet,
+		Path:     "/users?page=0&page_size=500",
+		Language: "en-US",
+	}).AssertStatus(t, http.StatusBadRequest).
+		AssertErrorCode(t, rest.ValidationError).
+		AssertErrorDetails(t, []rest.FieldError{
+			{Field: "page", Rule: "min", Param: "1", Message: "page must be at least 1"},
+			{Field: "page_size", Rule: "max", Param: "100", Message: "page_size must be at most 100"},
+			{Field: "Keyword", Rule: "required", Message: "Keyword is required"},
+		})
+}